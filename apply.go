@@ -0,0 +1,686 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Apply mutates target so that it becomes equal to the right side of the comparison.
+// target must be a non-nil pointer to a value shaped like the left side.
+func (dr *DiffResult) Apply(target any) error {
+	return dr.patch(target, false)
+}
+
+// Revert mutates target so that it becomes equal to the left side of the comparison.
+// target must be a non-nil pointer to a value shaped like the right side.
+func (dr *DiffResult) Revert(target any) error {
+	return dr.patch(target, true)
+}
+
+// sliceChangeGroup collects the element insertions and removals recorded for one slice
+type sliceChangeGroup struct {
+	path  string
+	steps Path
+	diffs []*SliceDiff
+}
+
+// patch applies (or, if reverse is set, undoes) every recorded difference on target.
+// Slice insertions and removals are applied per slice as a batch so that the
+// recorded indexes stay valid; all other differences are applied one by one.
+func (dr *DiffResult) patch(target any, reverse bool) error {
+	rootPtr := reflect.ValueOf(target)
+	if rootPtr.Kind() != reflect.Pointer || rootPtr.IsNil() {
+		return fmt.Errorf("patch target must be a non-nil pointer, got %T", target)
+	}
+	root := rootPtr.Elem()
+
+	groups, others := groupSliceChanges(dr.Diffs)
+
+	applyGroups := func() error {
+		for i := range groups {
+			g := groups[i]
+			if reverse {
+				g = groups[len(groups)-1-i]
+			}
+			if err := patchAt(root, g.steps, func(v reflect.Value) (reflect.Value, error) {
+				return patchSliceGroup(v, g.diffs, reverse)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !reverse {
+		if err := applyGroups(); err != nil {
+			return err
+		}
+	}
+
+	for i := range others {
+		diff := others[i]
+		if reverse {
+			diff = others[len(others)-1-i]
+		}
+		if err := patchDiff(root, diff, reverse); err != nil {
+			return err
+		}
+	}
+
+	if reverse {
+		return applyGroups()
+	}
+	return nil
+}
+
+// groupSliceChanges separates slice insertions and removals, grouped per slice with
+// enclosing slices first, from all other differences
+func groupSliceChanges(diffs []Change) ([]*sliceChangeGroup, []Change) {
+	var groups []*sliceChangeGroup
+	var others []Change
+	for _, diff := range diffs {
+		if d, ok := diff.(*SliceDiff); ok && isStructuralSliceChange(d.ChangeType) {
+			idx := slices.IndexFunc(groups, func(g *sliceChangeGroup) bool { return g.path == d.Path })
+			if idx < 0 {
+				groups = append(groups, &sliceChangeGroup{path: d.Path, steps: containerSteps(d)})
+				idx = len(groups) - 1
+			}
+			groups[idx].diffs = append(groups[idx].diffs, d)
+			continue
+		}
+		others = append(others, diff)
+	}
+	// nested slices are addressed by the indexes of the patched enclosing slices,
+	// so enclosing slices come first
+	slices.SortStableFunc(groups, func(a, b *sliceChangeGroup) int { return len(a.steps) - len(b.steps) })
+	return groups, others
+}
+
+// isStructuralSliceChange reports whether a slice change inserts, removes or moves elements
+func isStructuralSliceChange(ct ChangeType) bool {
+	return ct == ChangeTypeAdded || ct == ChangeTypeRemoved || ct == ChangeTypeMoved
+}
+
+// patchDiff applies a single non-structural difference to root
+func patchDiff(root reflect.Value, diff Change, reverse bool) error {
+	switch d := diff.(type) {
+	case *MapDiff:
+		return patchAt(root, containerSteps(d), func(v reflect.Value) (reflect.Value, error) {
+			return patchMapEntry(v, d, reverse)
+		})
+	case *SliceDiff:
+		return patchAt(root, containerSteps(d), func(v reflect.Value) (reflect.Value, error) {
+			return withContainer(v, func(s reflect.Value) (reflect.Value, error) {
+				if s.Kind() != reflect.Slice && s.Kind() != reflect.Array {
+					return reflect.Value{}, fmt.Errorf("slice diff at %q targets %s", d.Path, s.Type())
+				}
+				if d.Index < 0 || d.Index >= s.Len() {
+					return reflect.Value{}, fmt.Errorf("slice index %d out of range at %q", d.Index, d.Path)
+				}
+				elem, err := convertForAssign(pick(d.Left, d.Right, reverse), s.Index(d.Index))
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+				}
+				s.Index(d.Index).Set(elem)
+				return s, nil
+			})
+		})
+	case *StructDiff:
+		return patchAt(root, valueSteps(&d.Diff), func(v reflect.Value) (reflect.Value, error) {
+			nv, err := convertForAssign(pick(d.Left, d.Right, reverse), v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+			}
+			return nv, nil
+		})
+	case *Diff:
+		return patchAt(root, valueSteps(d), func(v reflect.Value) (reflect.Value, error) {
+			return patchValue(v, d, reverse)
+		})
+	default:
+		return fmt.Errorf("cannot apply unknown diff type %T", diff)
+	}
+}
+
+// patchValue replaces v with the target side of d. Diffs recorded for unordered slice
+// comparison carry a single element on one side; those are appended to or removed
+// from the slice instead of replacing it.
+func patchValue(v reflect.Value, d *Diff, reverse bool) (reflect.Value, error) {
+	from, to := d.Left, d.Right
+	if reverse {
+		from, to = to, from
+	}
+
+	if isSliceElementChange(v, from, to) {
+		if to != nil {
+			return withContainer(v, func(s reflect.Value) (reflect.Value, error) {
+				return reflect.Append(s, reflect.ValueOf(to)), nil
+			})
+		}
+		return withContainer(v, func(s reflect.Value) (reflect.Value, error) {
+			for i := range s.Len() {
+				if reflect.DeepEqual(s.Index(i).Interface(), from) {
+					return reflect.AppendSlice(s.Slice(0, i), s.Slice(i+1, s.Len())), nil
+				}
+			}
+			return reflect.Value{}, fmt.Errorf("element %v not found at %q", from, d.Path)
+		})
+	}
+
+	nv, err := convertForAssign(to, v)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+	}
+	return nv, nil
+}
+
+// isSliceElementChange reports whether a plain Diff recorded at a slice adds or removes
+// a single element, as unordered slice comparison does, rather than replacing the slice
+func isSliceElementChange(container reflect.Value, from, to any) bool {
+	s := indirectValue(container)
+	if s.Kind() != reflect.Slice || (from == nil) == (to == nil) {
+		return false
+	}
+	elemType := reflect.TypeOf(from)
+	if elemType == nil {
+		elemType = reflect.TypeOf(to)
+	}
+	return !elemType.AssignableTo(s.Type()) && elemType.AssignableTo(s.Type().Elem())
+}
+
+// patchMapEntry sets or deletes the entry recorded by d in the map held by v
+func patchMapEntry(v reflect.Value, d *MapDiff, reverse bool) (reflect.Value, error) {
+	return withContainer(v, func(m reflect.Value) (reflect.Value, error) {
+		if m.Kind() != reflect.Map {
+			return reflect.Value{}, fmt.Errorf("map diff at %q targets %s", d.Path, m.Type())
+		}
+		key, err := convertForAssign(d.Key, reflect.New(m.Type().Key()).Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+		}
+
+		changeType := d.ChangeType
+		if reverse {
+			changeType = invertChangeType(changeType)
+		}
+		if changeType == ChangeTypeRemoved {
+			if !m.IsNil() {
+				m.SetMapIndex(key, reflect.Value{})
+			}
+			return m, nil
+		}
+
+		elem, err := convertForAssign(pick(d.Left, d.Right, reverse), reflect.New(m.Type().Elem()).Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+		}
+		if m.IsNil() {
+			m = reflect.MakeMap(m.Type())
+		}
+		m.SetMapIndex(key, elem)
+		return m, nil
+	})
+}
+
+// patchSliceGroup applies the insertions and removals of one slice: removals first,
+// from the highest index down, then insertions from the lowest index up
+func patchSliceGroup(v reflect.Value, diffs []*SliceDiff, reverse bool) (reflect.Value, error) {
+	return withContainer(v, func(s reflect.Value) (reflect.Value, error) {
+		if s.Kind() != reflect.Slice {
+			return reflect.Value{}, fmt.Errorf("cannot insert into or remove from %s", s.Type())
+		}
+
+		// a moved element is removed at its source index and inserted at its target index
+		type sliceOp struct {
+			diff  *SliceDiff
+			index int
+		}
+		var removals, insertions []*sliceOp
+		for _, d := range diffs {
+			from, to := d.Index, d.Index
+			if d.ChangeType == ChangeTypeMoved {
+				from, to = d.FromIndex, d.ToIndex
+				if reverse {
+					from, to = to, from
+				}
+				removals = append(removals, &sliceOp{diff: d, index: from})
+				insertions = append(insertions, &sliceOp{diff: d, index: to})
+				continue
+			}
+			changeType := d.ChangeType
+			if reverse {
+				changeType = invertChangeType(changeType)
+			}
+			if changeType == ChangeTypeRemoved {
+				removals = append(removals, &sliceOp{diff: d, index: from})
+			} else {
+				insertions = append(insertions, &sliceOp{diff: d, index: to})
+			}
+		}
+		slices.SortFunc(removals, func(a, b *sliceOp) int { return b.index - a.index })
+		slices.SortFunc(insertions, func(a, b *sliceOp) int { return a.index - b.index })
+
+		moved := make(map[*SliceDiff]reflect.Value)
+		for _, op := range removals {
+			if op.index < 0 || op.index >= s.Len() {
+				return reflect.Value{}, fmt.Errorf("slice index %d out of range at %q", op.index, op.diff.Path)
+			}
+			if op.diff.ChangeType == ChangeTypeMoved {
+				elem := reflect.New(s.Type().Elem()).Elem()
+				elem.Set(s.Index(op.index))
+				moved[op.diff] = elem
+			}
+			s = reflect.AppendSlice(s.Slice(0, op.index), s.Slice(op.index+1, s.Len()))
+		}
+
+		for _, op := range insertions {
+			d := op.diff
+			if op.index < 0 || op.index > s.Len() {
+				return reflect.Value{}, fmt.Errorf("slice index %d out of range at %q", op.index, d.Path)
+			}
+			elem, isMove := moved[d]
+			if !isMove {
+				var err error
+				elem, err = convertForAssign(pick(d.Left, d.Right, reverse), reflect.New(s.Type().Elem()).Elem())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("%w at %q", err, d.Path)
+				}
+			}
+			grown := reflect.MakeSlice(s.Type(), 0, s.Len()+1)
+			grown = reflect.AppendSlice(grown, s.Slice(0, op.index))
+			grown = reflect.Append(grown, elem)
+			s = reflect.AppendSlice(grown, s.Slice(op.index, s.Len()))
+		}
+		return s, nil
+	})
+}
+
+// valueSteps returns the typed location of d, parsing the dotted Path for diffs
+// that were recorded without Steps
+func valueSteps(d *Diff) Path {
+	if d.Steps != nil {
+		return d.Steps
+	}
+	return parsedSteps(d.Path)
+}
+
+// containerSteps returns the typed location of the slice or map holding the
+// element recorded by a SliceDiff or MapDiff
+func containerSteps(diff Change) Path {
+	switch d := diff.(type) {
+	case *SliceDiff:
+		if d.Steps != nil {
+			parent, _ := d.Steps.Parent()
+			return parent
+		}
+		return parsedSteps(d.Path)
+	case *MapDiff:
+		parent, _ := valueSteps(&d.Diff).Parent()
+		return parent
+	default:
+		return nil
+	}
+}
+
+// patchAt walks path below root and replaces the value found there with the result of fn
+func patchAt(root reflect.Value, path Path, fn func(reflect.Value) (reflect.Value, error)) error {
+	nv, err := modifyAt(root, path, path, fn)
+	if err != nil {
+		return err
+	}
+	root.Set(nv)
+	return nil
+}
+
+// modifyAt descends into cur along steps and returns cur with the addressed value
+// replaced by the result of fn. Values that are not addressable (map entries, interface
+// contents, struct and array copies) are copied, modified and written back on the way up.
+// Pointers and interfaces are followed implicitly, so their steps need no handling.
+func modifyAt(cur reflect.Value, steps, path Path, fn func(reflect.Value) (reflect.Value, error)) (reflect.Value, error) {
+	for len(steps) > 0 && (steps[0].Kind == StepPointer || steps[0].Kind == StepInterface) {
+		steps = steps[1:]
+	}
+	if len(steps) == 0 {
+		return fn(cur)
+	}
+
+	switch cur.Kind() {
+	case reflect.Pointer:
+		if cur.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil pointer while resolving %q", path)
+		}
+		elem := cur.Elem()
+		nv, err := modifyAt(elem, steps, path, fn)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem.Set(nv)
+		return cur, nil
+	case reflect.Interface:
+		if cur.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil interface while resolving %q", path)
+		}
+		return modifyAt(addressableCopy(cur.Elem()), steps, path, fn)
+	}
+
+	if isJSONDocument(cur) {
+		return withJSONDocument(cur, func(doc reflect.Value) (reflect.Value, error) {
+			return modifyAt(doc, steps, path, fn)
+		})
+	}
+
+	step, rest := steps[0], steps[1:]
+	var container, elem reflect.Value
+	switch cur.Kind() {
+	case reflect.Struct:
+		container = addressableCopy(cur)
+		elem = fieldByStep(container, step)
+		if !elem.IsValid() || !elem.CanSet() {
+			return reflect.Value{}, fmt.Errorf("no settable field for %s in %s while resolving %q", stepString(step), cur.Type(), path)
+		}
+	case reflect.Slice, reflect.Array:
+		container = cur
+		if cur.Kind() == reflect.Array {
+			container = addressableCopy(cur)
+		}
+		elem = indexByStep(container, step)
+		if !elem.IsValid() {
+			return reflect.Value{}, fmt.Errorf("invalid index %s for %s while resolving %q", stepString(step), cur.Type(), path)
+		}
+	case reflect.Map:
+		key, ok := mapKeyByStep(cur, step)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no map entry %s while resolving %q", stepString(step), path)
+		}
+		nv, err := modifyAt(addressableCopy(cur.MapIndex(key)), rest, path, fn)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		cur.SetMapIndex(key, nv)
+		return cur, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot descend into %s while resolving %q", cur.Type(), path)
+	}
+
+	nv, err := modifyAt(elem, rest, path, fn)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	elem.Set(nv)
+	return container, nil
+}
+
+// isJSONDocument reports whether v holds a JSON document compared with `diff:"json"` or
+// by the RawJSONHandler, which paths descend into
+func isJSONDocument(v reflect.Value) bool {
+	return v.Kind() == reflect.String || v.Type() == reflect.TypeFor[json.RawMessage]()
+}
+
+// withJSONDocument calls fn with the decoded JSON document held by v and returns v with
+// the modified document encoded again
+func withJSONDocument(v reflect.Value, fn func(reflect.Value) (reflect.Value, error)) (reflect.Value, error) {
+	var doc any
+	if err := json.Unmarshal(jsonDocument(v), &doc); err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid JSON document in %s: %w", v.Type(), err)
+	}
+	nv, err := fn(reflect.ValueOf(&doc).Elem())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	encoded, err := json.Marshal(nv.Interface())
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot encode JSON document: %w", err)
+	}
+	out := reflect.New(v.Type()).Elem()
+	if v.Kind() == reflect.String {
+		out.SetString(string(encoded))
+	} else {
+		out.SetBytes(encoded)
+	}
+	return out, nil
+}
+
+// lookupPath returns the value addressed by steps below v, or an invalid
+// Value if the path does not exist
+func lookupPath(v reflect.Value, steps Path) reflect.Value {
+	for _, step := range steps {
+		if step.Kind == StepPointer || step.Kind == StepInterface {
+			continue
+		}
+		v = indirectValue(v)
+		switch v.Kind() {
+		case reflect.Struct:
+			v = fieldByStep(v, step)
+		case reflect.Slice, reflect.Array:
+			v = indexByStep(v, step)
+		case reflect.Map:
+			key, ok := mapKeyByStep(v, step)
+			if !ok {
+				return reflect.Value{}
+			}
+			v = v.MapIndex(key)
+		default:
+			return reflect.Value{}
+		}
+		if !v.IsValid() {
+			return v
+		}
+	}
+	return v
+}
+
+// fieldByStep returns the exported struct field named by a field step. Steps parsed from
+// paths rendered with NamingJSON or NamingYAML are matched by their tag names.
+func fieldByStep(s reflect.Value, step PathStep) reflect.Value {
+	if step.Kind != StepField {
+		return reflect.Value{}
+	}
+	field, ok := s.Type().FieldByName(step.Name)
+	if !ok {
+		field, ok = fieldByTagName(s.Type(), step.Name)
+	}
+	if !ok || !field.IsExported() {
+		return reflect.Value{}
+	}
+	return s.FieldByIndex(field.Index)
+}
+
+// fieldByTagName finds the field of a struct type whose json or yaml name is name
+func fieldByTagName(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for _, field := range reflect.VisibleFields(typ) {
+		for _, naming := range []PathNaming{NamingJSON, NamingYAML} {
+			if tagName, omit := naming(field); !omit && tagName == name {
+				return field, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// indexByStep returns the slice or array element addressed by an index step, or by
+// an identity step: the element whose key field matches, falling back to the recorded
+// index for keys that are not struct fields
+func indexByStep(s reflect.Value, step PathStep) reflect.Value {
+	switch step.Kind {
+	case StepIndex:
+	case StepIdentity:
+		if i, hasField := identityIndex(s, step); hasField {
+			if i < 0 {
+				return reflect.Value{}
+			}
+			return s.Index(i)
+		}
+	default:
+		return reflect.Value{}
+	}
+	if step.Index < 0 || step.Index >= s.Len() {
+		return reflect.Value{}
+	}
+	return s.Index(step.Index)
+}
+
+// identityIndex finds the element of s whose field named by an identity step holds the
+// step's key, compared directly or by formatted form for keys from parsed paths. index
+// is -1 if no element matches; hasField is false if the elements have no such field.
+func identityIndex(s reflect.Value, step PathStep) (index int, hasField bool) {
+	key := &sliceKey{name: step.Name, field: step.Name}
+	for i := range s.Len() {
+		k, found := key.of(s.Index(i))
+		if !found {
+			continue
+		}
+		hasField = true
+		if k == step.Key || fmt.Sprintf("%v", k) == fmt.Sprintf("%v", step.Key) {
+			return i, true
+		}
+	}
+	return -1, hasField
+}
+
+// mapKeyByStep returns the key of m addressed by step. Keys recorded during comparison
+// are used as is; keys from parsed paths are matched by their formatted form.
+func mapKeyByStep(m reflect.Value, step PathStep) (reflect.Value, bool) {
+	var keyStr string
+	switch step.Kind {
+	case StepMapKey:
+		if step.Key != nil && reflect.TypeOf(step.Key).AssignableTo(m.Type().Key()) {
+			key := reflect.ValueOf(step.Key)
+			if m.MapIndex(key).IsValid() {
+				return key, true
+			}
+		}
+		keyStr = fmt.Sprintf("%v", step.Key)
+	case StepIndex:
+		keyStr = strconv.Itoa(step.Index)
+	case StepIdentity:
+		keyStr = fmt.Sprintf("%s=%v", step.Name, step.Key)
+	case StepField:
+		keyStr = step.Name
+	default:
+		return reflect.Value{}, false
+	}
+
+	if m.Type().Key().Kind() == reflect.String {
+		key := reflect.ValueOf(keyStr).Convert(m.Type().Key())
+		return key, m.MapIndex(key).IsValid()
+	}
+	for _, key := range m.MapKeys() {
+		if fmt.Sprintf("%v", key.Interface()) == keyStr {
+			return key, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// stepString renders a single step for error messages
+func stepString(step PathStep) string {
+	if step.Kind == StepField {
+		return step.Name
+	}
+	return Path{step}.String()
+}
+
+// withContainer calls fn with the container held by v, dereferencing pointers
+// (allocating them when nil) and unwrapping interfaces, and returns v updated accordingly
+func withContainer(v reflect.Value, fn func(reflect.Value) (reflect.Value, error)) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		ptr := v
+		if ptr.IsNil() {
+			ptr = reflect.New(v.Type().Elem())
+		}
+		nv, err := withContainer(ptr.Elem(), fn)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr.Elem().Set(nv)
+		return ptr, nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("cannot modify nil %s", v.Type())
+		}
+		return withContainer(addressableCopy(v.Elem()), fn)
+	default:
+		if isJSONDocument(v) {
+			return withJSONDocument(v, func(doc reflect.Value) (reflect.Value, error) {
+				return withContainer(doc, fn)
+			})
+		}
+		return fn(addressableCopy(v))
+	}
+}
+
+// indirectValue follows pointers and interfaces until it reaches a concrete value
+func indirectValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// addressableCopy returns a settable copy of v
+func addressableCopy(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+// convertForAssign converts value so that it can be stored in slot. A nil value yields
+// the zero value; a value of a pointer's element type is stored through the pointer,
+// allocating it if needed.
+func convertForAssign(value any, slot reflect.Value) (reflect.Value, error) {
+	typ := slot.Type()
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+	if value == Redacted {
+		return reflect.Value{}, fmt.Errorf("cannot assign a redacted value to %s", typ)
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Type().AssignableTo(typ) {
+		return val, nil
+	}
+
+	if typ.Kind() == reflect.Pointer && val.Type().AssignableTo(typ.Elem()) {
+		ptr := slot
+		if ptr.IsNil() {
+			ptr = reflect.New(typ.Elem())
+		}
+		ptr.Elem().Set(val)
+		return ptr, nil
+	}
+
+	if isNumericKind(val.Kind()) && isNumericKind(typ.Kind()) && val.Type().ConvertibleTo(typ) {
+		return val.Convert(typ), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot assign %T to %s", value, typ)
+}
+
+// pick returns the value a patch should write: the right side when applying,
+// the left side when reverting
+func pick(left, right any, reverse bool) any {
+	if reverse {
+		return left
+	}
+	return right
+}
+
+// invertChangeType swaps ADDED and REMOVED
+func invertChangeType(ct ChangeType) ChangeType {
+	switch ct {
+	case ChangeTypeAdded:
+		return ChangeTypeRemoved
+	case ChangeTypeRemoved:
+		return ChangeTypeAdded
+	default:
+		return ct
+	}
+}
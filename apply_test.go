@@ -0,0 +1,271 @@
+package godiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+type applyOrder struct {
+	ID     int
+	Status string
+	Tags   []string
+}
+
+type applyAccount struct {
+	Name    string
+	Age     int
+	Address *Address
+	Orders  []applyOrder
+	Scores  map[string]int
+	Meta    map[string]any
+	Extra   any
+	Labels  []string `diff:"ignoreOrder"`
+	Matrix  [2]int
+	Friends []*Person
+}
+
+func newApplyAccounts() (applyAccount, applyAccount) {
+	left := applyAccount{
+		Name:    "Alice",
+		Age:     30,
+		Address: &Address{Street: "Main St", City: "Berlin"},
+		Orders: []applyOrder{
+			{ID: 1, Status: "open", Tags: []string{"a"}},
+			{ID: 2, Status: "open"},
+			{ID: 3, Status: "closed"},
+		},
+		Scores:  map[string]int{"math": 1, "art": 2},
+		Meta:    map[string]any{"nested": map[string]any{"k": "v"}, "gone": true},
+		Extra:   applyOrder{ID: 9, Status: "x"},
+		Labels:  []string{"x", "y", "z"},
+		Matrix:  [2]int{1, 2},
+		Friends: []*Person{{Name: "Bob"}},
+	}
+	right := applyAccount{
+		Name:    "Alicia",
+		Age:     31,
+		Address: &Address{Street: "Main St", City: "Hamburg"},
+		Orders: []applyOrder{
+			{ID: 1, Status: "shipped", Tags: []string{"a", "b"}},
+			{ID: 2, Status: "open"},
+		},
+		Scores:  map[string]int{"math": 3, "music": 4},
+		Meta:    map[string]any{"nested": map[string]any{"k": "w"}},
+		Extra:   applyOrder{ID: 9, Status: "y"},
+		Labels:  []string{"z", "w", "x"},
+		Matrix:  [2]int{1, 5},
+		Friends: []*Person{{Name: "Bob", Age: 40}, {Name: "Carol"}},
+	}
+	return left, right
+}
+
+func TestApply(t *testing.T) {
+	left, right := newApplyAccounts()
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	target, _ := newApplyAccounts()
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	after, err := Compare(target, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if after.HasDifferences() {
+		t.Errorf("Expected no differences after Apply, got %s", after.String())
+	}
+}
+
+func TestRevert(t *testing.T) {
+	left, right := newApplyAccounts()
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	_, target := newApplyAccounts()
+	if err := result.Revert(&target); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+
+	after, err := Compare(target, left)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if after.HasDifferences() {
+		t.Errorf("Expected no differences after Revert, got %s", after.String())
+	}
+}
+
+func TestApplyCollections(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  any
+		right any
+		opts  []CompareOption
+	}{
+		{"slice grows", []int{1, 2}, []int{1, 2, 3, 4}, nil},
+		{"slice shrinks", []int{1, 2, 3, 4}, []int{9}, nil},
+		{"unordered slice", []string{"a", "b", "c"}, []string{"c", "d"}, []CompareOption{WithIgnoreSliceOrder()}},
+		{"map", map[int]string{1: "a", 2: "b"}, map[int]string{2: "c", 3: "d"}, nil},
+		{"nested maps", map[string]map[string]int{"x": {"a": 1}}, map[string]map[string]int{"x": {"a": 2, "b": 3}, "y": {}}, nil},
+		{"pointer set", &Address{City: "A"}, &Address{City: "B", Country: "C"}, nil},
+		{"slice of structs", []Address{{City: "A"}}, []Address{{City: "B"}, {City: "C"}}, nil},
+		{"moved elements", []string{"a", "b", "c", "d", "e"}, []string{"d", "b", "x", "a", "e", "c"}, []CompareOption{WithDetectMoves()}},
+		{"lcs slice", []string{"a", "b", "c", "d"}, []string{"x", "a", "c", "y", "d", "z"}, []CompareOption{WithSliceAlgorithm(SliceLCS)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+
+			target := reflect.New(reflect.TypeOf(tt.left))
+			target.Elem().Set(reflect.ValueOf(clone(t, tt.left)))
+			if err := result.Apply(target.Interface()); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			after, err := Compare(target.Elem().Interface(), tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if after.HasDifferences() {
+				t.Errorf("Expected no differences after Apply, got %s", after.String())
+			}
+
+			if err := result.Revert(target.Interface()); err != nil {
+				t.Fatalf("Revert failed: %v", err)
+			}
+			after, err = Compare(target.Elem().Interface(), tt.left, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if after.HasDifferences() {
+				t.Errorf("Expected no differences after Revert, got %s", after.String())
+			}
+		})
+	}
+}
+
+func TestApplySliceLCS(t *testing.T) {
+	orders := func() ([]applyOrder, []applyOrder) {
+		left := []applyOrder{{ID: 1, Tags: []string{"a"}}, {ID: 2}, {ID: 4, Tags: []string{"x", "y"}}}
+		right := []applyOrder{{ID: 0}, {ID: 1, Tags: []string{"a", "b"}}, {ID: 3}, {ID: 4, Tags: []string{"y"}}}
+		return left, right
+	}
+
+	left, right := orders()
+	result, err := Compare(left, right, WithSliceAlgorithm(SliceLCS))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	target, _ := orders()
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if after, err := Compare(target, right); err != nil || after.HasDifferences() {
+		t.Errorf("Expected %+v after Apply, got %+v", right, target)
+	}
+
+	_, target = orders()
+	if err := result.Revert(&target); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	if after, err := Compare(target, left); err != nil || after.HasDifferences() {
+		t.Errorf("Expected %+v after Revert, got %+v", left, target)
+	}
+}
+
+func TestApplySliceKey(t *testing.T) {
+	type keyedAccount struct {
+		Orders []applyOrder `diff:"key=ID"`
+	}
+	accounts := func() (keyedAccount, keyedAccount) {
+		left := keyedAccount{Orders: []applyOrder{{ID: 1, Status: "open"}, {ID: 2, Status: "open"}, {ID: 3}}}
+		right := keyedAccount{Orders: []applyOrder{{ID: 3, Tags: []string{"a"}}, {ID: 4}, {ID: 1, Status: "open"}}}
+		return left, right
+	}
+
+	for _, opts := range [][]CompareOption{nil, {WithDetectMoves()}} {
+		left, right := accounts()
+		result, err := Compare(left, right, opts...)
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+
+		target, _ := accounts()
+		if err := result.Apply(&target); err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if after, err := Compare(target, right, opts...); err != nil || after.HasDifferences() {
+			t.Errorf("Expected %+v after Apply, got %+v", right, target)
+		}
+
+		_, target = accounts()
+		if err := result.Revert(&target); err != nil {
+			t.Fatalf("Revert failed: %v", err)
+		}
+		if after, err := Compare(target, left, opts...); err != nil || after.HasDifferences() {
+			t.Errorf("Expected %+v after Revert, got %+v", left, target)
+		}
+	}
+}
+
+// clone copies v deep enough that applying a patch to the copy leaves the fixture untouched
+func clone(t *testing.T, v any) any {
+	t.Helper()
+	cp := reflect.New(reflect.TypeOf(v))
+	switch val := reflect.ValueOf(v); val.Kind() {
+	case reflect.Slice:
+		cp.Elem().Set(reflect.AppendSlice(reflect.MakeSlice(val.Type(), 0, val.Len()), val))
+	case reflect.Map:
+		m := reflect.MakeMap(val.Type())
+		for _, k := range val.MapKeys() {
+			m.SetMapIndex(k, reflect.ValueOf(clone(t, val.MapIndex(k).Interface())))
+		}
+		cp.Elem().Set(m)
+	case reflect.Pointer:
+		p := reflect.New(val.Type().Elem())
+		p.Elem().Set(val.Elem())
+		cp.Elem().Set(p)
+	default:
+		cp.Elem().Set(val)
+	}
+	return cp.Elem().Interface()
+}
+
+func TestApplyErrors(t *testing.T) {
+	result, err := Compare("a", "b")
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	if err := result.Apply("a"); err == nil {
+		t.Error("Expected error for non-pointer target")
+	}
+
+	var nilPtr *string
+	if err := result.Apply(nilPtr); err == nil {
+		t.Error("Expected error for nil pointer target")
+	}
+
+	number := 1
+	if err := result.Apply(&number); err == nil {
+		t.Error("Expected error for mismatched target type")
+	}
+
+	result, err = Compare(Person{Address: &Address{City: "A"}}, Person{Address: &Address{City: "B"}})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if err := result.Apply(&Person{}); err == nil {
+		t.Error("Expected error when path crosses a nil pointer")
+	}
+}
@@ -2,7 +2,11 @@ package godiff
 
 import (
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type Person struct {
@@ -699,7 +703,7 @@ func TestCompareValuesEdgeCases(t *testing.T) {
 		result := &DiffResult{}
 		config := DefaultCompareConfig()
 		config.IgnoreFields = []string{"test"}
-		err := compareValues("test", "left", "right", result, config)
+		err := compareValues(Path{}.field("test"), "left", "right", result, config)
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -711,7 +715,7 @@ func TestCompareValuesEdgeCases(t *testing.T) {
 	t.Run("identical reference types", func(t *testing.T) {
 		result := &DiffResult{}
 		slice := []int{1, 2, 3}
-		err := compareValues("test", slice, slice, result, DefaultCompareConfig())
+		err := compareValues(Path{}.field("test"), slice, slice, result, DefaultCompareConfig())
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -728,7 +732,7 @@ func TestCompareValuesEdgeCases(t *testing.T) {
 				return left == right, nil
 			},
 		}
-		err := compareValues("test", "same", "same", result, config)
+		err := compareValues(Path{}.field("test"), "same", "same", result, config)
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -753,7 +757,7 @@ func TestCompareStructsEdgeCases(t *testing.T) {
 		leftVal := reflect.ValueOf(left)
 		rightVal := reflect.ValueOf(right)
 
-		err := compareStructs("test", leftVal, rightVal, result, DefaultCompareConfig())
+		err := compareStructs(Path{}.field("test"), leftVal, rightVal, result, DefaultCompareConfig())
 		if err != nil {
 			t.Fatalf("compareStructs failed: %v", err)
 		}
@@ -776,7 +780,7 @@ func TestCompareStructsEdgeCases(t *testing.T) {
 		leftVal := reflect.ValueOf(left)
 		rightVal := reflect.ValueOf(right)
 
-		err := compareStructs("test", leftVal, rightVal, result, DefaultCompareConfig())
+		err := compareStructs(Path{}.field("test"), leftVal, rightVal, result, DefaultCompareConfig())
 		if err != nil {
 			t.Fatalf("compareStructs failed: %v", err)
 		}
@@ -786,3 +790,221 @@ func TestCompareStructsEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestCompareOf(t *testing.T) {
+	type money struct {
+		Amount   int64
+		Currency string
+	}
+	type invoice struct {
+		Number string
+		Total  money
+		Lines  []money
+	}
+
+	left := invoice{Number: "1", Total: money{100, "eur"}, Lines: []money{{100, "eur"}}}
+	right := invoice{Number: "2", Total: money{100, "EUR"}, Lines: []money{{100, "EUR"}}}
+
+	sameMoney := func(a, b money) bool {
+		return a.Amount == b.Amount && strings.EqualFold(a.Currency, b.Currency)
+	}
+	result, err := CompareOf(left, right, WithComparator(sameMoney))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].Location().String() != "Number" {
+		t.Errorf("Expected only Number to differ, got %s", result.String())
+	}
+
+	comparators := map[reflect.Type]func(left, right any, config *CompareConfig) (bool, error){}
+	if _, err := CompareOf(left, right, WithCustomComparators(comparators), WithComparator(sameMoney)); err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(comparators) != 0 {
+		t.Errorf("Expected the caller's comparator map to stay unchanged, got %d entries", len(comparators))
+	}
+
+	type tagged struct {
+		Tags []string
+	}
+	sameTags := WithComparator(func(a, b []string) bool {
+		return strings.EqualFold(strings.Join(a, ","), strings.Join(b, ","))
+	})
+	l, r := tagged{Tags: []string{"a", "b"}}, tagged{Tags: []string{"A", "B"}}
+	if result, err := CompareOf(l, r, sameTags); err != nil || result.HasDifferences() {
+		t.Errorf("Expected the slice comparator to be used for a struct field, got %v, %v", result, err)
+	}
+	differ, err := NewDiffer[tagged](sameTags)
+	if err != nil {
+		t.Fatalf("NewDiffer failed: %v", err)
+	}
+	if result, err := differ.Compare(l, r); err != nil || result.HasDifferences() {
+		t.Errorf("Expected the Differ to use the slice comparator for a struct field, got %v, %v", result, err)
+	}
+}
+
+// probeHandler handles no type and counts how often it is asked
+type probeHandler struct {
+	probes atomic.Int64
+}
+
+func (h *probeHandler) CanHandle(typ reflect.Type) bool {
+	h.probes.Add(1)
+	return false
+}
+
+func (h *probeHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	return nil
+}
+
+func TestDiffer(t *testing.T) {
+	type item struct {
+		SKU   string
+		Price float64 `diff:"tolerance=0.01"`
+	}
+	type order struct {
+		ID    int
+		Items []item `diff:"key=SKU"`
+		Note  string `diff:"ignore"`
+	}
+
+	differ, err := NewDiffer[order](WithIgnoreFields("ID"))
+	if err != nil {
+		t.Fatalf("NewDiffer failed: %v", err)
+	}
+
+	left := order{ID: 1, Items: []item{{"a", 1}, {"b", 2}}, Note: "x"}
+	right := order{ID: 2, Items: []item{{"b", 2.005}, {"a", 1.5}}, Note: "y"}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			result, err := differ.Compare(left, right)
+			if err != nil {
+				t.Errorf("Compare failed: %v", err)
+				return
+			}
+			if len(result.Diffs) != 1 || result.Diffs[0].Location().String() != "Items[SKU=a].Price" {
+				t.Errorf("Expected only Items[SKU=a].Price to differ, got %s", result.String())
+			}
+		})
+	}
+	wg.Wait()
+
+	probe := &probeHandler{}
+	differ, err = NewDiffer[order](WithTypeHandlers([]TypeHandler{probe}))
+	if err != nil {
+		t.Fatalf("NewDiffer failed: %v", err)
+	}
+	probes := probe.probes.Load()
+	for range 3 {
+		if _, err := differ.Compare(left, right); err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+	}
+	if got := probe.probes.Load(); got != probes {
+		t.Errorf("Expected the type handlers to be resolved once by NewDiffer, got %d more probes", got-probes)
+	}
+
+	type badTag struct {
+		Name string `diff:"bogus"`
+	}
+	type wrapper struct {
+		Inner map[string][]*badTag
+	}
+	if _, err := NewDiffer[wrapper](); err == nil || !strings.Contains(err.Error(), `unknown directive "bogus"`) {
+		t.Errorf("Expected NewDiffer to report the invalid tag of a nested type, got %v", err)
+	}
+	if _, err := NewDiffer[order](WithIgnoreFields("re:[")); err == nil {
+		t.Error("Expected NewDiffer to report an invalid ignore pattern")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	type item struct {
+		Name  string
+		Count int
+	}
+	type record struct {
+		ID      int
+		Items   []item
+		Tags    []string
+		Seen    time.Time
+		Comment string `diff:"ignore"`
+	}
+
+	now := time.Now()
+	base := record{ID: 1, Items: []item{{"a", 1}}, Tags: []string{"x", "y"}, Seen: now, Comment: "c"}
+
+	tests := []struct {
+		name  string
+		right record
+		opts  []CompareOption
+	}{
+		{"identical", base, nil},
+		{"different field", record{ID: 2, Items: base.Items, Tags: base.Tags, Seen: now}, nil},
+		{"ignored by tag", record{ID: 1, Items: base.Items, Tags: base.Tags, Seen: now, Comment: "d"}, nil},
+		{"ignored by option", record{ID: 2, Items: base.Items, Tags: base.Tags, Seen: now}, []CompareOption{WithIgnoreFields("ID")}},
+		{"slice order", record{ID: 1, Items: base.Items, Tags: []string{"y", "x"}, Seen: now}, nil},
+		{"slice order ignored", record{ID: 1, Items: base.Items, Tags: []string{"y", "x"}, Seen: now}, []CompareOption{WithIgnoreSliceOrder()}},
+		{"time handler", record{ID: 1, Items: base.Items, Tags: base.Tags, Seen: now.In(time.UTC)}, nil},
+		{"outside only fields", record{ID: 2, Items: []item{{"a", 1}, {"b", 2}}, Tags: base.Tags, Seen: now}, []CompareOption{WithOnlyFields("Items[*].Name")}},
+		{"inside only fields", record{ID: 1, Items: []item{{"b", 1}}, Tags: base.Tags, Seen: now}, []CompareOption{WithOnlyFields("Items[*].Name")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(base, tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if got, want := Equal(base, tt.right, tt.opts...), !result.HasDifferences(); got != want {
+				t.Errorf("Expected Equal to return %v, got %v: %s", want, got, result.String())
+			}
+		})
+	}
+
+	if !Equal(int32(1), 1.0, WithCompareNumericValues()) || Equal(int32(1), 1.0) {
+		t.Error("Expected numeric comparison to follow WithCompareNumericValues")
+	}
+	if _, err := EqualE(base, base, WithIgnoreFields("re:[")); err == nil {
+		t.Error("Expected EqualE to report invalid options")
+	}
+	type badTag struct {
+		Name string `diff:"bogus"`
+	}
+	if _, err := EqualE(badTag{"a"}, badTag{"a"}); err == nil || !strings.Contains(err.Error(), `unknown directive "bogus"`) {
+		t.Errorf("Expected EqualE to report an invalid diff tag, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected Equal to panic on an invalid diff tag")
+			}
+		}()
+		Equal(badTag{"a"}, badTag{"b"})
+	}()
+
+	calls := 0
+	countingEqual := WithComparator(func(a, b item) bool {
+		calls++
+		return a == b
+	})
+	left, right := make([]item, 1000), make([]item, 1000)
+	for i := range right {
+		right[i].Count = 1
+	}
+	if Equal(left, right, countingEqual) {
+		t.Error("Expected different slices to be unequal")
+	}
+	if calls != 1 {
+		t.Errorf("Expected Equal to stop after the first difference, compared %d elements", calls)
+	}
+
+	differ, err := NewDiffer[record]()
+	if err != nil {
+		t.Fatalf("NewDiffer failed: %v", err)
+	}
+	if !differ.Equal(base, base) || differ.Equal(base, record{}) {
+		t.Error("Expected Differ.Equal to match Equal")
+	}
+}
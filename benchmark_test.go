@@ -59,6 +59,16 @@ func BenchmarkCompareStructs(b *testing.B) {
 	}
 }
 
+func BenchmarkEqual(b *testing.B) {
+	b.ReportAllocs()
+	left := createLargeBenchmarkStruct(1)
+	right := createLargeBenchmarkStruct(2)
+
+	for b.Loop() {
+		_ = Equal(left, right)
+	}
+}
+
 func BenchmarkCompareSlices(b *testing.B) {
 	b.ReportAllocs()
 	left := make([]int, 100)
@@ -74,6 +84,19 @@ func BenchmarkCompareSlices(b *testing.B) {
 	}
 }
 
+func BenchmarkCompareSlicesLCS(b *testing.B) {
+	b.ReportAllocs()
+	left := make([]int, 1000)
+	for i := range left {
+		left[i] = i
+	}
+	right := append([]int{-1}, left...)
+
+	for b.Loop() {
+		_, _ = Compare(left, right, WithSliceAlgorithm(SliceLCS))
+	}
+}
+
 func BenchmarkCompareSlicesWithIgnoreOrder(b *testing.B) {
 	b.ReportAllocs()
 	type TestStruct struct {
@@ -1,7 +1,11 @@
 package godiff
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -213,8 +217,8 @@ func TestMapWithDifferentValueTypes(t *testing.T) {
 			if mapDiff.Right != 123 {
 				t.Errorf("Expected right value 123, got %v", mapDiff.Right)
 			}
-			if mapDiff.ChangeType != "UPDATED" {
-				t.Errorf("Expected UPDATED change type, got %s", mapDiff.ChangeType)
+			if mapDiff.ChangeType != ChangeTypeTypeChanged {
+				t.Errorf("Expected TYPE_CHANGED change type, got %s", mapDiff.ChangeType)
 			}
 		} else {
 			t.Error("Expected MapDiff type for map value change")
@@ -685,7 +689,7 @@ func TestCompareSlicesAdvanced(t *testing.T) {
 			leftVal := reflect.ValueOf(tt.left)
 			rightVal := reflect.ValueOf(tt.right)
 
-			err := compareSlicesAdvanced("test", leftVal, rightVal, result)
+			err := compareSlicesAdvanced(Path{}.field("test"), leftVal, rightVal, result)
 			if err != nil {
 				t.Fatalf("compareSlicesAdvanced failed: %v", err)
 			}
@@ -703,7 +707,7 @@ func TestCompareMapsEdgeCases(t *testing.T) {
 		leftVal := reflect.ValueOf(map[string]int(nil))
 		rightVal := reflect.ValueOf(map[string]int(nil))
 
-		err := compareMaps("test", leftVal, rightVal, result, DefaultCompareConfig())
+		err := compareMaps(Path{}.field("test"), leftVal, rightVal, result, DefaultCompareConfig())
 		if err != nil {
 			t.Fatalf("compareMaps failed: %v", err)
 		}
@@ -718,7 +722,7 @@ func TestCompareMapsEdgeCases(t *testing.T) {
 		leftVal := reflect.ValueOf(map[string]int{})
 		rightVal := reflect.ValueOf(map[string]int{})
 
-		err := compareMaps("test", leftVal, rightVal, result, DefaultCompareConfig())
+		err := compareMaps(Path{}.field("test"), leftVal, rightVal, result, DefaultCompareConfig())
 		if err != nil {
 			t.Fatalf("compareMaps failed: %v", err)
 		}
@@ -736,7 +740,7 @@ func TestCompareMapsEdgeCases(t *testing.T) {
 		leftVal := reflect.ValueOf(left)
 		rightVal := reflect.ValueOf(right)
 
-		err := compareMaps("test", leftVal, rightVal, result, DefaultCompareConfig())
+		err := compareMaps(Path{}.field("test"), leftVal, rightVal, result, DefaultCompareConfig())
 		if err != nil {
 			t.Fatalf("compareMaps failed: %v", err)
 		}
@@ -746,3 +750,408 @@ func TestCompareMapsEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestSliceLCS(t *testing.T) {
+	large := make([]int, 1000)
+	for i := range large {
+		large[i] = i
+	}
+	prepended := append([]int{-1}, large...)
+
+	t.Run("insertion at front", func(t *testing.T) {
+		result, err := Compare(large, prepended, WithSliceAlgorithm(SliceLCS))
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 1 {
+			t.Fatalf("Expected 1 diff, got %d", len(result.Diffs))
+		}
+		d := result.Diffs[0].(*SliceDiff)
+		if d.ChangeType != ChangeTypeAdded || d.Index != 0 || d.FromIndex != -1 || d.ToIndex != 0 || d.Right != -1 {
+			t.Errorf("Unexpected diff %+v", d)
+		}
+
+		positional, err := Compare(large, prepended)
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(positional.Diffs) != 1001 {
+			t.Errorf("Expected positional algorithm to remain the default, got %d diffs", len(positional.Diffs))
+		}
+	})
+
+	t.Run("limit bounds the search", func(t *testing.T) {
+		left, right := make([]int, 20000), make([]int, 20000)
+		for i := range left {
+			left[i], right[i] = i, -i-1
+		}
+		for _, opts := range [][]CompareOption{{WithSliceAlgorithm(SliceLCS)}, {WithDetectMoves()}} {
+			result, err := Compare(left, right, append(opts, WithMaxDiffs(10))...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if !result.Truncated || len(result.Diffs) != 10 {
+				t.Errorf("Expected 10 diffs in a truncated result, got %d (truncated %v)", len(result.Diffs), result.Truncated)
+			}
+			if Equal(left, right, opts...) {
+				t.Error("Expected slices to differ")
+			}
+		}
+
+		result, err := Compare(large, prepended, WithSliceAlgorithm(SliceLCS), WithMaxDiffs(2))
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if result.Truncated || len(result.Diffs) != 1 || result.Diffs[0].Kind() != ChangeTypeAdded {
+			t.Errorf("Expected an alignment within the limit to be reported, got %s", result.String())
+		}
+	})
+
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		expected []string
+	}{
+		{
+			name:     "deletion in the middle",
+			left:     []string{"a", "b", "c", "d"},
+			right:    []string{"a", "c", "d"},
+			expected: []string{"removed [1] 1->-1"},
+		},
+		{
+			name:     "replacement is an update",
+			left:     []string{"a", "b", "c"},
+			right:    []string{"x", "a", "y", "c"},
+			expected: []string{"added [0] -1->0", "updated [2] 1->2"},
+		},
+		{
+			name:     "more deletions than insertions",
+			left:     []int{1, 2, 3, 4},
+			right:    []int{1, 9, 4},
+			expected: []string{"updated [1] 1->1", "removed [2] 2->-1"},
+		},
+		{
+			name:     "nested changes use the right index",
+			left:     []Address{{City: "A"}, {City: "B", Street: "1"}},
+			right:    []Address{{City: "Z"}, {City: "A"}, {City: "B", Street: "2"}},
+			expected: []string{"added [0] -1->0", "updated [2].Street"},
+		},
+		{
+			name:     "equal slices",
+			left:     []int{1, 2},
+			right:    []int{1, 2},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, WithSliceAlgorithm(SliceLCS))
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			var got []string
+			for _, change := range result.Diffs {
+				switch d := change.(type) {
+				case *SliceDiff:
+					got = append(got, fmt.Sprintf("%s %s %d->%d", d.ChangeType, d.Steps, d.FromIndex, d.ToIndex))
+				default:
+					got = append(got, fmt.Sprintf("%s %s", change.Kind(), change.Location()))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSliceKey(t *testing.T) {
+	type order struct {
+		ID     int
+		Status string
+	}
+	type customer struct {
+		Name   string
+		Orders []order `diff:"key=id"`
+	}
+
+	left := customer{Orders: []order{{ID: 41, Status: "open"}, {ID: 42, Status: "open"}, {ID: 43, Status: "open"}}}
+	right := customer{Orders: []order{{ID: 42, Status: "shipped"}, {ID: 41, Status: "open"}, {ID: 44, Status: "new"}}}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	expected := []string{
+		"UPDATED Orders[id=42].Status: open -> shipped",
+		"REMOVED Orders[id=43]: {43 open}",
+		"ADDED Orders[id=44]: {44 new}",
+	}
+	if got := strings.Split(strings.TrimSpace(result.String()), "\n")[1:]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if d := result.Diffs[2].(*SliceDiff); d.Index != 2 || d.FromIndex != -1 || d.ToIndex != 2 {
+		t.Errorf("Expected added order at right index 2, got %+v", d)
+	}
+
+	t.Run("option with field", func(t *testing.T) {
+		result, err := Compare(left.Orders, right.Orders, WithSliceKey("", "ID"))
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 3 || result.Diffs[0].Location().String() != "[ID=42].Status" {
+			t.Errorf("Unexpected diffs: %s", result.String())
+		}
+	})
+
+	t.Run("option with function on nested path", func(t *testing.T) {
+		type account struct {
+			Customers []customer
+		}
+		byStatus := func(elem any) any { return elem.(order).Status }
+		result, err := Compare(
+			account{Customers: []customer{{Orders: []order{{ID: 1, Status: "a"}, {ID: 2, Status: "b"}}}}},
+			account{Customers: []customer{{Orders: []order{{ID: 3, Status: "b"}, {ID: 1, Status: "a"}}}}},
+			WithSliceKey("Customers.Orders", byStatus),
+		)
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 1 || result.Diffs[0].Location().String() != "Customers[0].Orders[key=b].ID" {
+			t.Errorf("Unexpected diffs: %s", result.String())
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		if _, err := Compare(left, right, WithSliceKey("Orders", 42)); err == nil {
+			t.Error("Expected error for invalid slice key")
+		}
+	})
+
+	t.Run("elements without key", func(t *testing.T) {
+		result, err := Compare([]*order{nil, {ID: 1}}, []*order{{ID: 1, Status: "x"}}, WithSliceKey("", "ID"))
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 2 || result.Diffs[0].Kind() != ChangeTypeRemoved || result.Diffs[1].Location().String() != "[ID=1].Status" {
+			t.Errorf("Unexpected diffs: %s", result.String())
+		}
+	})
+}
+
+func TestDetectMoves(t *testing.T) {
+	t.Run("reordered elements", func(t *testing.T) {
+		result, err := Compare([]string{"a", "b", "c", "d"}, []string{"b", "c", "a", "d"}, WithDetectMoves())
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 1 {
+			t.Fatalf("Expected 1 diff, got %d: %s", len(result.Diffs), result.String())
+		}
+		d := result.Diffs[0].(*SliceDiff)
+		if d.ChangeType != ChangeTypeMoved || d.FromIndex != 0 || d.ToIndex != 2 || d.Index != 2 || d.Left != "a" {
+			t.Errorf("Unexpected move %+v", d)
+		}
+		if !strings.Contains(result.String(), "MOVED [2]: a (from index 0)") {
+			t.Errorf("Expected move in output, got %s", result.String())
+		}
+		if !strings.Contains(result.ToJSON(), `"fromIndex": 0`) {
+			t.Errorf("Expected fromIndex in JSON output, got %s", result.ToJSON())
+		}
+	})
+
+	t.Run("moves mixed with edits", func(t *testing.T) {
+		result, err := Compare([]int{1, 2, 3, 4}, []int{4, 1, 5, 3}, WithDetectMoves())
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		var got []string
+		for _, change := range result.Diffs {
+			d := change.(*SliceDiff)
+			got = append(got, fmt.Sprintf("%s %d->%d", d.ChangeType, d.FromIndex, d.ToIndex))
+		}
+		expected := []string{"moved 3->0", "updated 1->2"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("identity keyed elements", func(t *testing.T) {
+		type order struct {
+			ID     int
+			Status string
+		}
+		left := []order{{ID: 1}, {ID: 2}, {ID: 3}}
+		right := []order{{ID: 3, Status: "x"}, {ID: 1}, {ID: 2}}
+		result, err := Compare(left, right, WithSliceKey("", "ID"), WithDetectMoves())
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		expected := []string{"MOVED [ID=3]: {3 x} (from index 2)", "UPDATED [ID=3].Status:  -> x"}
+		if got := strings.Split(strings.TrimSpace(result.String()), "\n")[1:]; !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+
+		unordered, err := Compare(left, right, WithSliceKey("", "ID"))
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(unordered.Diffs) != 1 {
+			t.Errorf("Expected keyed matching without move detection to ignore order, got %s", unordered.String())
+		}
+	})
+}
+
+func TestMaxDiffs(t *testing.T) {
+	left, right := make([]int, 100000), make([]int, 100000)
+	for i := range right {
+		right[i] = 1
+	}
+
+	result, err := Compare(left, right, WithMaxDiffs(100))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !result.Truncated || len(result.Diffs) != 100 {
+		t.Fatalf("Expected 100 diffs in a truncated result, got %d (truncated %v)", len(result.Diffs), result.Truncated)
+	}
+	if result.EstimatedTotal != 100000 {
+		t.Errorf("Expected an estimate of 100000 diffs, got %d", result.EstimatedTotal)
+	}
+	if !strings.HasPrefix(result.String(), "Found 100 differences (truncated, about 100000 in total):\n") {
+		t.Errorf("Expected truncation in output, got %s", result.String()[:80])
+	}
+
+	type row struct {
+		Cells []int
+	}
+	type table struct {
+		Name string
+		Rows []row
+	}
+	l, r := table{Name: "a"}, table{Name: "b"}
+	for range 10 {
+		l.Rows = append(l.Rows, row{Cells: make([]int, 10)})
+		r.Rows = append(r.Rows, row{Cells: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}})
+	}
+	tests := []struct {
+		name      string
+		opts      []CompareOption
+		diffs     int
+		truncated bool
+		estimate  int
+	}{
+		{"nested", []CompareOption{WithMaxDiffs(26)}, 26, true, 104},
+		{"exact", []CompareOption{WithMaxDiffs(101)}, 101, false, 101},
+		{"unlimited", nil, 101, false, 101},
+		{"ignore order", []CompareOption{WithMaxDiffs(5), WithIgnoreSliceOrder()}, 5, true, 25},
+		{"only fields", []CompareOption{WithMaxDiffs(5), WithOnlyFields("Rows[*].Cells")}, 5, true, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(l, r, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if len(result.Diffs) != tt.diffs || result.Truncated != tt.truncated || result.EstimatedTotal != tt.estimate {
+				t.Errorf("Expected %d diffs, truncated %v, estimate %d; got %d, %v, %d",
+					tt.diffs, tt.truncated, tt.estimate, len(result.Diffs), result.Truncated, result.EstimatedTotal)
+			}
+		})
+	}
+
+	shifted := make([]int, len(right))
+	for i := range shifted {
+		left[i], shifted[i] = i, i+len(left)
+	}
+	result, err = Compare(left, shifted, WithMaxDiffs(10), WithIgnoreSliceOrder())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !result.Truncated || len(result.Diffs) != 10 || result.EstimatedTotal != 200000 {
+		t.Errorf("Expected 10 of about 200000 diffs when ignoring order, got %d of %d (truncated %v)", len(result.Diffs), result.EstimatedTotal, result.Truncated)
+	}
+	for _, diff := range result.Diffs {
+		if v, ok := diff.base().Left.(int); ok && v >= len(left) {
+			t.Errorf("Expected only values missing on the right to be removed, got %v", v)
+		}
+	}
+
+	type element struct {
+		X int
+	}
+	type mixed struct {
+		A []element
+		D json.RawMessage
+	}
+	result, err = Compare(
+		mixed{D: json.RawMessage(`{"a":1,"b":1}`)},
+		mixed{A: []element{{1}, {2}}, D: json.RawMessage(`{"a":2,"b":1}`)},
+		WithOnlyFields("A[*].X", "D"), WithMaxDiffs(3),
+	)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].Location().String() != "D.a" {
+		t.Errorf("Expected only D.a inside the allowed fields, got %s", result.String())
+	}
+
+	type documents struct {
+		Raw  json.RawMessage
+		Text string `diff:"json"`
+	}
+	values := func(v int) string {
+		return "[" + strings.TrimSuffix(strings.Repeat(strconv.Itoa(v)+",", 10), ",") + "]"
+	}
+	for _, tt := range []struct {
+		name        string
+		left, right documents
+	}{
+		{"raw json", documents{Raw: json.RawMessage(values(0))}, documents{Raw: json.RawMessage(values(1))}},
+		{"json field", documents{Text: values(0)}, documents{Text: values(1)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, WithMaxDiffs(3))
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if len(result.Diffs) != 3 || !result.Truncated || result.EstimatedTotal != 10 {
+				t.Errorf("Expected 3 of about 10 diffs in a truncated result, got %s", result.String())
+			}
+		})
+	}
+
+	scores, rescored := make(map[int]int), make(map[int]int)
+	unordered, reordered := make([]int, 100), make([]int, 100)
+	for i := range 100 {
+		scores[i], rescored[i] = i, i+1
+		unordered[i], reordered[i] = i, i+100
+	}
+	type point struct {
+		X, Y, Z int
+	}
+	for _, tt := range []struct {
+		name        string
+		left, right any
+		limit       int
+		opts        []CompareOption
+		estimate    int
+		output      string
+	}{
+		{"map", scores, rescored, 10, nil, 100, "Found 10 differences (truncated, about 100 in total):\n"},
+		{"unordered", unordered, reordered, 10, []CompareOption{WithIgnoreSliceOrder()}, 200, "Found 10 differences (truncated, about 200 in total):\n"},
+		{"struct", point{1, 2, 3}, point{4, 5, 6}, 2, nil, 0, "Found 2 differences (truncated):\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, append(tt.opts, WithMaxDiffs(tt.limit))...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if result.EstimatedTotal != tt.estimate || !strings.HasPrefix(result.String(), tt.output) {
+				t.Errorf("Expected an estimate of %d, got %d in %s", tt.estimate, result.EstimatedTotal, result.String())
+			}
+		})
+	}
+}
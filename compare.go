@@ -1,23 +1,95 @@
 package godiff
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
 	"slices"
-	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unsafe"
 )
 
 // CompareOption is a function that modifies a CompareConfig
 type CompareOption func(*CompareConfig)
 
-// WithIgnoreFields sets the fields to ignore during comparison
+// WithIgnoreFields sets the fields to ignore during comparison. Besides field names and
+// paths, wildcard patterns like "Items[*].UpdatedAt", "Meta[*]" or "**.Secret" and regular
+// expressions prefixed with "re:" are accepted. Plain field names match fields of that name
+// at any depth, wildcard and regex patterns only the full rendered path.
 func WithIgnoreFields(fields ...string) CompareOption {
 	return func(c *CompareConfig) {
 		c.IgnoreFields = fields
 	}
 }
 
+// WithOnlyFields restricts the comparison to the given paths and everything below them.
+// Ancestors of the paths are descended into, everything else is skipped. Paths may use the
+// wildcard patterns of WithIgnoreFields, e.g. "Orders[*].Total".
+func WithOnlyFields(paths ...string) CompareOption {
+	return func(c *CompareConfig) {
+		c.OnlyFields = paths
+	}
+}
+
+// WithFilter adds a predicate called before descending into every struct field, map entry
+// and slice element. Values for which it returns false are skipped, e.g. fields tagged
+// `json:"-"`. When slice order is ignored, elements are filtered before they are matched,
+// each described as present on its own side only:
+//
+//	godiff.WithFilter(func(ctx godiff.FilterContext) bool {
+//		return ctx.Field == nil || ctx.Field.Tag.Get("json") != "-"
+//	})
+func WithFilter(filter func(ctx FilterContext) bool) CompareOption {
+	return func(c *CompareConfig) {
+		c.Filters = append(c.Filters, filter)
+	}
+}
+
+// WithPathNaming sets how struct fields are named in paths, StructDiff.FieldName and the
+// patterns of WithIgnoreFields and WithOnlyFields, e.g. NamingJSON for json tag names
+func WithPathNaming(naming PathNaming) CompareOption {
+	return func(c *CompareConfig) {
+		c.PathNaming = naming
+	}
+}
+
+// WithSkipOmittedFields skips struct fields the PathNaming omits, such as fields tagged
+// `json:"-"` with NamingJSON
+func WithSkipOmittedFields() CompareOption {
+	return func(c *CompareConfig) {
+		c.SkipOmittedFields = true
+	}
+}
+
+// WithUnexportedFields compares the unexported fields of all structs. Changes to unexported
+// fields are reported but cannot be applied.
+func WithUnexportedFields() CompareOption {
+	return func(c *CompareConfig) {
+		c.UnexportedFields = true
+	}
+}
+
+// WithUnexportedFieldsOf compares the unexported fields of the struct types of the given
+// values, e.g. WithUnexportedFieldsOf(Account{}, &Order{})
+func WithUnexportedFieldsOf(types ...any) CompareOption {
+	return func(c *CompareConfig) {
+		if c.UnexportedTypes == nil {
+			c.UnexportedTypes = make(map[reflect.Type]bool, len(types))
+		}
+		for _, v := range types {
+			typ := reflect.TypeOf(v)
+			for typ != nil && typ.Kind() == reflect.Pointer {
+				typ = typ.Elem()
+			}
+			c.UnexportedTypes[typ] = true
+		}
+	}
+}
+
 // WithIgnoreSliceOrder enables ignoring slice element order during comparison
 func WithIgnoreSliceOrder() CompareOption {
 	return func(c *CompareConfig) {
@@ -25,6 +97,52 @@ func WithIgnoreSliceOrder() CompareOption {
 	}
 }
 
+// WithSliceAlgorithm sets the algorithm used to compare ordered slices
+func WithSliceAlgorithm(algorithm SliceAlgorithm) CompareOption {
+	return func(c *CompareConfig) {
+		c.SliceAlgorithm = algorithm
+	}
+}
+
+// WithSliceKey pairs the elements of the slices at path by identity instead of by position
+// or value. key is the name of the struct field holding the identity (matched case-insensitively
+// and used as the name in identity path steps such as `Orders[ID=42]`) or a func(elem any) any
+// returning it. path is a dotted path like "Customer.Orders" or the path's field names alone,
+// which also matches slices nested below other slices or maps.
+func WithSliceKey(path string, key any) CompareOption {
+	return func(c *CompareConfig) {
+		if c.SliceKeys == nil {
+			c.SliceKeys = make(map[string]any)
+		}
+		c.SliceKeys[path] = key
+	}
+}
+
+// WithDetectMoves reports ordered slice elements that changed position as MOVED diffs
+// instead of removals and additions. Ordered slices are aligned with SliceLCS, and slices
+// matched by identity key are checked for elements whose relative order changed.
+func WithDetectMoves() CompareOption {
+	return func(c *CompareConfig) {
+		c.DetectMoves = true
+	}
+}
+
+// WithNilEqualsEmpty treats nil slices, maps, pointers and interfaces, and absent map
+// entries, as equal to empty slices and maps
+func WithNilEqualsEmpty() CompareOption {
+	return func(c *CompareConfig) {
+		c.NilEqualsEmpty = true
+	}
+}
+
+// WithZeroEqualsMissing treats nil pointers and interfaces, and absent map entries, as
+// equal to zero values
+func WithZeroEqualsMissing() CompareOption {
+	return func(c *CompareConfig) {
+		c.ZeroEqualsMissing = true
+	}
+}
+
 // WithCompareNumericValues enables comparing numeric values across different types
 func WithCompareNumericValues() CompareOption {
 	return func(c *CompareConfig) {
@@ -32,6 +150,49 @@ func WithCompareNumericValues() CompareOption {
 	}
 }
 
+// WithFloatTolerance treats float and complex values as equal when they differ by at most
+// abs, or by at most rel times the larger magnitude. Complex values are compared by their
+// real and imaginary parts.
+func WithFloatTolerance(abs, rel float64) CompareOption {
+	return func(c *CompareConfig) {
+		c.FloatAbsTolerance = abs
+		c.FloatRelTolerance = rel
+	}
+}
+
+// WithTimeTolerance treats time.Time values as equal when they are at most d apart
+func WithTimeTolerance(d time.Duration) CompareOption {
+	return func(c *CompareConfig) {
+		c.TimeTolerance = d
+	}
+}
+
+// WithTimeTruncate truncates time.Time values to a multiple of d before comparing them
+func WithTimeTruncate(d time.Duration) CompareOption {
+	return func(c *CompareConfig) {
+		c.TimeTruncate = d
+	}
+}
+
+// WithTextDiff attaches a line-based unified diff with the given number of context lines
+// to changes of multi-line strings
+func WithTextDiff(contextLines int) CompareOption {
+	return func(c *CompareConfig) {
+		c.TextDiffs = true
+		c.TextDiffContext = contextLines
+	}
+}
+
+// WithEditScripts attaches a character-level edit script to changes of strings of at most
+// maxLength runes, or of any length if maxLength is 0. Multi-line strings get a line diff
+// instead if WithTextDiff is set.
+func WithEditScripts(maxLength int) CompareOption {
+	return func(c *CompareConfig) {
+		c.EditScripts = true
+		c.EditScriptMaxLength = maxLength
+	}
+}
+
 // WithCustomComparators sets custom comparison functions for specific types
 func WithCustomComparators(comparators map[reflect.Type]func(left, right any, config *CompareConfig) (bool, error)) CompareOption {
 	return func(c *CompareConfig) {
@@ -39,6 +200,33 @@ func WithCustomComparators(comparators map[reflect.Type]func(left, right any, co
 	}
 }
 
+// WithComparator registers a comparison function for values of type T, which must be a
+// concrete type. It returns true if the values are equal.
+func WithComparator[T any](equal func(a, b T) bool) CompareOption {
+	return func(c *CompareConfig) {
+		// the map may have been passed to WithCustomComparators by the caller
+		comparators := maps.Clone(c.CustomComparators)
+		if comparators == nil {
+			comparators = make(map[reflect.Type]func(left, right any, config *CompareConfig) (bool, error))
+		}
+		comparators[reflect.TypeFor[T]()] = func(left, right any, config *CompareConfig) (bool, error) {
+			return equal(left.(T), right.(T)), nil
+		}
+		c.CustomComparators = comparators
+	}
+}
+
+// WithNamedComparator registers a comparison function under a name, for struct fields
+// tagged `diff:"compare=name"`. It returns true if the values are equal.
+func WithNamedComparator(name string, comparator func(left, right any, config *CompareConfig) (bool, error)) CompareOption {
+	return func(c *CompareConfig) {
+		if c.NamedComparators == nil {
+			c.NamedComparators = make(map[string]func(left, right any, config *CompareConfig) (bool, error))
+		}
+		c.NamedComparators[name] = comparator
+	}
+}
+
 // WithTypeHandlers sets the type handlers for comparing custom or complex types
 func WithTypeHandlers(handlers []TypeHandler) CompareOption {
 	return func(c *CompareConfig) {
@@ -46,6 +234,23 @@ func WithTypeHandlers(handlers []TypeHandler) CompareOption {
 	}
 }
 
+// WithEqualMethods compares values of types with an `Equal(T) bool`, `Cmp(T) int` or
+// `Compare(T) int` method by calling it, see EqualMethodHandler. The configured type
+// handlers take precedence.
+func WithEqualMethods() CompareOption {
+	return func(c *CompareConfig) {
+		c.EqualMethods = true
+	}
+}
+
+// WithMaxDiffs stops the comparison once n changes are recorded and marks the result as
+// Truncated. 0 means unlimited.
+func WithMaxDiffs(n int) CompareOption {
+	return func(c *CompareConfig) {
+		c.MaxDiffs = n
+	}
+}
+
 // WithMaxDepth sets the maximum recursion depth for comparison (0 means unlimited)
 func WithMaxDepth(depth int) CompareOption {
 	return func(c *CompareConfig) {
@@ -56,6 +261,15 @@ func WithMaxDepth(depth int) CompareOption {
 // Compare compares two values of any type and returns the differences.
 // Optional configuration can be provided via CompareOption functions.
 func Compare(left, right any, opts ...CompareOption) (*DiffResult, error) {
+	config, err := newCompareConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return runCompare(left, right, config, config.MaxDiffs)
+}
+
+// newCompareConfig applies options to the default configuration and compiles its patterns
+func newCompareConfig(opts []CompareOption) (*CompareConfig, error) {
 	config := DefaultCompareConfig()
 
 	for _, opt := range opts {
@@ -66,35 +280,157 @@ func Compare(left, right any, opts ...CompareOption) (*DiffResult, error) {
 		config.visitedPairs = make(map[[2]uintptr]bool)
 	}
 
-	if config.ignoreFieldsSet == nil && len(config.IgnoreFields) > 0 {
-		config.ignoreFieldsSet = make(map[string]bool, len(config.IgnoreFields))
-		for _, field := range config.IgnoreFields {
-			config.ignoreFieldsSet[field] = true
+	if config.ignoreMatcher == nil && len(config.IgnoreFields) > 0 {
+		matcher, err := newPathMatcher(config.IgnoreFields)
+		if err != nil {
+			return nil, err
+		}
+		config.ignoreMatcher = matcher
+	}
+	if len(config.OnlyFields) > 0 {
+		matcher, err := newAllowMatcher(config.OnlyFields)
+		if err != nil {
+			return nil, err
 		}
+		config.onlyMatcher = matcher
 	}
+	if len(config.SliceKeys) > 0 {
+		config.sliceKeys = make(map[string]*sliceKey, len(config.SliceKeys))
+	}
+	for path, key := range config.SliceKeys {
+		sk, err := newSliceKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slice key for %q: %w", path, err)
+		}
+		config.sliceKeys[path] = sk
+	}
+	return config, nil
+}
+
+// errDiffLimit aborts a comparison once the DiffResult holds as many changes as its limit
+var errDiffLimit = errors.New("diff limit reached")
+
+// runCompare compares two values with a configuration prepared by newCompareConfig,
+// stopping once limit changes are recorded if limit is positive
+func runCompare(left, right any, config *CompareConfig, limit int) (*DiffResult, error) {
 	config.currentDepth = 0
-	result := &DiffResult{}
-	err := compareValues("", left, right, result, config)
-	if err != nil {
+	result := &DiffResult{left: left, right: right, limit: limit, only: config.onlyMatcher}
+	err := compareValues(nil, left, right, result, config)
+	if err != nil && !errors.Is(err, errDiffLimit) {
 		return nil, err
 	}
+	if config.onlyMatcher != nil {
+		// changes recorded on ancestors of the allowed paths, such as added slice
+		// elements, lie outside the allow-list
+		result.Diffs = slices.DeleteFunc(result.Diffs, config.onlyMatcher.excludes)
+	}
+	if result.Truncated {
+		result.EstimatedTotal = result.estimateTotal()
+		// changes recorded without a limit check between them may exceed the limit
+		result.Diffs = result.Diffs[:min(len(result.Diffs), limit)]
+	} else {
+		result.EstimatedTotal = len(result.Diffs)
+	}
 	return result, nil
 }
 
+// Equal reports whether two values are equal. It honours the same options as Compare but
+// stops at the first difference. It panics if an option or a diff tag is invalid or if a
+// comparator or type handler fails; EqualE returns these errors instead.
+func Equal(left, right any, opts ...CompareOption) bool {
+	equal, err := EqualE(left, right, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return equal
+}
+
+// EqualE is Equal returning the errors of invalid options and diff tags, comparators and
+// type handlers
+func EqualE(left, right any, opts ...CompareOption) (bool, error) {
+	config, err := newCompareConfig(opts)
+	if err != nil {
+		return false, err
+	}
+	return equalWith(left, right, config)
+}
+
+// equalWith reports whether two values are equal under a prepared configuration
+func equalWith(left, right any, config *CompareConfig) (bool, error) {
+	config.TextDiffs, config.EditScripts = false, false
+	config.currentDepth = 0
+	return valuesEqual(nil, left, right, config)
+}
+
+// valuesEqual compares two values, stopping at the first difference
+func valuesEqual(path Path, left, right any, config *CompareConfig) (bool, error) {
+	scratch := &DiffResult{limit: 1, only: config.onlyMatcher, discard: config.onlyMatcher == nil}
+	if err := compareValues(path, left, right, scratch, config); err != nil && !errors.Is(err, errDiffLimit) {
+		return false, err
+	}
+	return scratch.counted() == 0, nil
+}
+
+// equalsAbsent reports whether two values are equal because one of them is missing or nil
+// and the other one is empty (NilEqualsEmpty) or zero (ZeroEqualsMissing)
+func (c *CompareConfig) equalsAbsent(left, right reflect.Value) bool {
+	if c.NilEqualsEmpty && isNilOrEmpty(left) && isNilOrEmpty(right) {
+		return true
+	}
+	return c.ZeroEqualsMissing && (isMissing(left) && isDeepZero(right) || isMissing(right) && isDeepZero(left))
+}
+
+// isNilOrEmpty reports whether v is missing, a nil or empty slice or map, or a pointer or
+// interface holding one
+func isNilOrEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil() || isNilOrEmpty(v.Elem())
+	}
+	return false
+}
+
+// isMissing reports whether v is invalid or a nil pointer or interface
+func isMissing(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isDeepZero reports whether v is missing or a zero value, looking through pointers and
+// interfaces
+func isDeepZero(v reflect.Value) bool {
+	if isMissing(v) {
+		return true
+	}
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		return isDeepZero(v.Elem())
+	}
+	return v.IsZero()
+}
+
 // handleInvalidValues checks if either value is invalid and records a diff if needed
 // Returns true if handled (one or both values invalid), false if both are valid
-func handleInvalidValues(path string, left, right any, leftVal, rightVal reflect.Value, result *DiffResult) bool {
+func handleInvalidValues(path Path, left, right any, leftVal, rightVal reflect.Value, result *DiffResult) bool {
 	if !leftVal.IsValid() && !rightVal.IsValid() {
 		return true // both invalid, no diff
 	}
 
 	if !leftVal.IsValid() {
-		result.AddDiff(path, nil, right)
+		result.addDiff(path, nil, right, ChangeTypeAdded)
 		return true
 	}
 
 	if !rightVal.IsValid() {
-		result.AddDiff(path, left, nil)
+		result.addDiff(path, left, nil, ChangeTypeRemoved)
 		return true
 	}
 
@@ -102,7 +438,10 @@ func handleInvalidValues(path string, left, right any, leftVal, rightVal reflect
 }
 
 // compareValues recursively compares two values and records differences
-func compareValues(path string, left, right any, result *DiffResult, config *CompareConfig) error {
+func compareValues(path Path, left, right any, result *DiffResult, config *CompareConfig) error {
+	if result.limitReached() {
+		return result.truncate(path)
+	}
 	if config.MaxDepth > 0 {
 		if config.currentDepth >= config.MaxDepth {
 			return nil
@@ -111,12 +450,18 @@ func compareValues(path string, left, right any, result *DiffResult, config *Com
 		defer func() { config.currentDepth-- }()
 	}
 
-	if config.ignoreFieldsSet != nil {
-		if config.ignoreFieldsSet[path] {
+	if len(config.IgnoreFields) > 0 && config.isPathIgnored(path) {
+		return nil
+	}
+
+	if config.onlyMatcher != nil && !config.insideOnlyFields {
+		allowed, ancestor := config.onlyMatcher.match(path)
+		if allowed {
+			config.insideOnlyFields = true
+			defer func() { config.insideOnlyFields = false }()
+		} else if !ancestor {
 			return nil
 		}
-	} else if slices.Contains(config.IgnoreFields, path) {
-		return nil
 	}
 
 	// Early exit: identical reference types (ptr/map/slice/chan/func) share same pointer
@@ -136,6 +481,10 @@ func compareValues(path string, left, right any, result *DiffResult, config *Com
 	leftVal := reflect.ValueOf(left)
 	rightVal := reflect.ValueOf(right)
 
+	if config.equalsAbsent(leftVal, rightVal) {
+		return nil
+	}
+
 	if handleInvalidValues(path, left, right, leftVal, rightVal, result) {
 		return nil
 	}
@@ -151,47 +500,29 @@ func compareValues(path string, left, right any, result *DiffResult, config *Com
 		}
 		// Check if both are numeric types and config allows cross-type numeric comparison
 		if config.CompareNumericValues && isNumericKind(leftVal.Kind()) && isNumericKind(rightVal.Kind()) {
-			if numericValuesEqual(leftVal, rightVal) {
+			if numericValuesWithinTolerance(leftVal, rightVal, config) {
 				return nil
 			}
-			result.Diffs = append(result.Diffs, &Diff{
-				Path:  path,
-				Left:  left,
-				Right: right,
-			})
+			result.addDiff(path, left, right, ChangeTypeUpdated)
 			return nil
 		}
-		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  left,
-			Right: right,
-		})
+		result.addDiff(path, left, right, ChangeTypeTypeChanged)
 		return nil
 	}
 
-	if config.CustomComparators != nil {
-		if customComparator, exists := config.CustomComparators[leftType]; exists {
-			equal, err := customComparator(left, right, config)
-			if err != nil {
-				return err
-			}
-			if !equal {
-				result.Diffs = append(result.Diffs, &Diff{
-					Path:  path,
-					Left:  left,
-					Right: right,
-				})
-			}
-			return nil
+	customComparator, handler := config.resolve(leftType)
+	if customComparator != nil {
+		equal, err := customComparator(left, right, config)
+		if err != nil {
+			return err
 		}
-	}
-
-	if config.TypeHandlers != nil {
-		for _, handler := range config.TypeHandlers {
-			if handler.CanHandle(leftType) {
-				return handler.Compare(left, right, path, result, config)
-			}
+		if !equal {
+			result.addDiff(path, left, right, ChangeTypeUpdated)
 		}
+		return nil
+	}
+	if handler != nil {
+		return compareWithHandler(handler, path, left, right, result, config)
 	}
 
 	leftKind := leftVal.Kind()
@@ -205,138 +536,286 @@ func compareValues(path string, left, right any, result *DiffResult, config *Com
 	case reflect.Pointer:
 		return comparePointers(path, leftVal, rightVal, result, config)
 	default:
+		if config.relaxesBasicValues() && isBasicKind(leftKind) {
+			if !basicValuesEqual(left, right, config) {
+				result.addDiff(path, left, right, changeTypeFor(left, right))
+				config.attachStringDiff(result)
+			}
+			return nil
+		}
 		if leftVal.Type().Comparable() {
 			if left != right {
-				result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+				result.addDiff(path, left, right, changeTypeFor(left, right))
+				config.attachStringDiff(result)
 			}
 			return nil
 		}
 		if !reflect.DeepEqual(left, right) {
-			result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+			result.addDiff(path, left, right, changeTypeFor(left, right))
 		}
 		return nil
 	}
 }
 
+// resolve returns the custom comparator or else the type handler comparing values of typ,
+// taken from the plan of a Differ if typ is part of it
+func (c *CompareConfig) resolve(typ reflect.Type) (comparator func(left, right any, config *CompareConfig) (bool, error), handler TypeHandler) {
+	if plan, ok := c.plans[typ]; ok {
+		return plan.comparator, plan.handler
+	}
+	return c.resolveType(typ)
+}
+
+// resolveType is resolve without the plan
+func (c *CompareConfig) resolveType(typ reflect.Type) (comparator func(left, right any, config *CompareConfig) (bool, error), handler TypeHandler) {
+	if comparator, ok := c.CustomComparators[typ]; ok {
+		return comparator, nil
+	}
+	for _, handler := range c.TypeHandlers {
+		if handler.CanHandle(typ) {
+			return nil, handler
+		}
+	}
+	if c.EqualMethods {
+		if handler := (&EqualMethodHandler{}); handler.CanHandle(typ) {
+			return nil, handler
+		}
+	}
+	return nil, nil
+}
+
+// fieldTags returns the parsed diff tags of a struct type, taken from the plan of a Differ
+// if typ is part of it
+func (c *CompareConfig) fieldTags(typ reflect.Type) ([]fieldTag, error) {
+	if plan, ok := c.plans[typ]; ok {
+		return plan.tags, nil
+	}
+	return tagsFor(typ)
+}
+
+// compareWithHandler runs a TypeHandler, which receives the path in its string form,
+// and attaches the typed path to the diffs it records
+func compareWithHandler(handler TypeHandler, path Path, left, right any, result *DiffResult, config *CompareConfig) error {
+	pathStr := path.String()
+	before := len(result.Diffs)
+	config.currentPath = path
+	err := handler.Compare(left, right, pathStr, result, config)
+	for _, diff := range result.Diffs[before:] {
+		d := diff.base()
+		if d.Steps != nil {
+			continue
+		}
+		if d.Path == pathStr {
+			d.Steps = path
+		} else {
+			d.Steps = parsedSteps(d.Path)
+		}
+	}
+	return err
+}
+
 // isFieldIgnored checks if a field should be ignored based on IgnoreFields configuration
 // It checks multiple patterns:
 // 1. Simple field name (e.g., "Meta")
 // 2. Full path (e.g., "User.Meta" or "Address.City")
 // 3. Type-qualified field name (e.g., "MyStruct.Meta")
+// Only the full path is matched against wildcard and regex patterns.
 func isFieldIgnored(fieldPath string, fieldName string, structType reflect.Type, config *CompareConfig) bool {
 	if len(config.IgnoreFields) == 0 {
 		return false
 	}
 
-	if config.ignoreFieldsSet != nil {
-		if config.ignoreFieldsSet[fieldPath] {
-			return true
-		}
+	matcher := config.pathMatcher()
+	if matcher.match(fieldPath) || matcher.exact[fieldName] {
+		return true
+	}
 
-		if config.ignoreFieldsSet[fieldName] {
-			return true
-		}
+	// wildcard and regex patterns only match the rendered path, plain names also match
+	// the bare and the type-qualified field name
+	structTypeName := structType.Name()
+	return structTypeName != "" && matcher.exact[structTypeName+"."+fieldName]
+}
 
-		structTypeName := structType.Name()
-		if structTypeName != "" {
-			typeQualifiedName := structTypeName + "." + fieldName
-			if config.ignoreFieldsSet[typeQualifiedName] {
-				return true
+// pathMatcher returns the compiled IgnoreFields patterns, compiling them on first use for
+// configs that did not pass through Compare. Invalid patterns are then matched literally.
+func (c *CompareConfig) pathMatcher() *pathMatcher {
+	if c.ignoreMatcher == nil {
+		matcher, err := newPathMatcher(c.IgnoreFields)
+		if err != nil {
+			matcher = &pathMatcher{exact: make(map[string]bool, len(c.IgnoreFields))}
+			for _, field := range c.IgnoreFields {
+				matcher.exact[field] = true
 			}
 		}
-		return false
-	}
-
-	// Fall back to slice search
-	if slices.Contains(config.IgnoreFields, fieldPath) {
-		return true
+		c.ignoreMatcher = matcher
 	}
+	return c.ignoreMatcher
+}
 
-	if slices.Contains(config.IgnoreFields, fieldName) {
-		return true
-	}
+// isPathIgnored reports whether a path matches one of the IgnoreFields patterns
+func (c *CompareConfig) isPathIgnored(path Path) bool {
+	return c.pathMatcher().match(path.String())
+}
 
-	structTypeName := structType.Name()
-	if structTypeName != "" {
-		typeQualifiedName := structTypeName + "." + fieldName
-		if slices.Contains(config.IgnoreFields, typeQualifiedName) {
+// isFiltered reports whether one of the Filters rejects the value described by ctx
+func (c *CompareConfig) isFiltered(ctx FilterContext) bool {
+	for _, filter := range c.Filters {
+		if !filter(ctx) {
 			return true
 		}
 	}
-
 	return false
 }
 
+// skipsElement reports whether the Filters reject the slice element at index of a slice
+// of type sliceType at path; left or right is nil if the element is missing on that side
+func (c *CompareConfig) skipsElement(path Path, sliceType reflect.Type, index int, left, right any) bool {
+	return len(c.Filters) > 0 && c.isFiltered(FilterContext{
+		Path: path.index(index), ParentType: sliceType, Index: index, Left: left, Right: right,
+	})
+}
+
+// isOutsideOnlyFields reports whether a path is neither allowed by OnlyFields nor an
+// ancestor of an allowed path
+func (c *CompareConfig) isOutsideOnlyFields(path Path) bool {
+	if c.onlyMatcher == nil || c.insideOnlyFields {
+		return false
+	}
+	allowed, ancestor := c.onlyMatcher.match(path)
+	return !allowed && !ancestor
+}
+
 // compareStructs compares two structs field by field
-func compareStructs(path string, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+func compareStructs(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
 	typ := leftVal.Type()
 	numFields := leftVal.NumField()
+	tags, err := config.fieldTags(typ)
+	if err != nil {
+		return err
+	}
+	unexported := config.readsUnexported(typ)
+	if unexported {
+		leftVal, rightVal = addressableCopy(leftVal), addressableCopy(rightVal)
+	}
 
 	for i := range numFields {
+		if result.limitReached() {
+			return result.truncate(path.field(typ.Field(i).Name))
+		}
 		field := typ.Field(i)
 		// Skip unexported fields to avoid calling Interface() on values we can't access from
-		// another package (this prevents panics for types like time.Time).
-		if !field.IsExported() {
+		// another package (this prevents panics for types like time.Time), unless they are
+		// read through unsafe pointers as requested by WithUnexportedFields.
+		if !field.IsExported() && !unexported {
+			continue
+		}
+		tag := &tags[i]
+		if tag.ignore {
 			continue
 		}
 
-		var fieldPath string
-		if path == "" {
-			fieldPath = field.Name
-		} else {
-			fieldPath = path + "." + field.Name
+		step := PathStep{Kind: StepField, Name: field.Name}
+		if config.PathNaming != nil {
+			name, omit := config.PathNaming(field)
+			if omit && config.SkipOmittedFields {
+				continue
+			}
+			if name != "" && name != field.Name {
+				step.Label = name
+			}
+		}
+		if tag.name != "" {
+			step.Label = tag.name
 		}
 
-		diffTag := field.Tag.Get("diff")
-		if isFieldIgnored(fieldPath, field.Name, typ, config) || hasDiffTag(diffTag, "ignore") {
+		if (len(config.IgnoreFields) > 0 && isFieldIgnored(path.with(step).String(), step.fieldName(), typ, config)) ||
+			config.onlyMatcher != nil && config.isOutsideOnlyFields(path.with(step)) {
 			continue
 		}
 
 		leftField := leftVal.Field(i)
 		rightField := rightVal.Field(i)
+		if !field.IsExported() {
+			leftField, rightField = exposeField(leftField), exposeField(rightField)
+		}
+		comparator, handler := config.resolve(field.Type)
+		// equal basic values need neither a comparison nor boxing into interfaces
+		if len(config.Filters) == 0 && tag.comparator == "" && comparator == nil && handler == nil &&
+			basicFieldsEqual(leftField, rightField) {
+			continue
+		}
 		leftFieldInterface := leftField.Interface()
 		rightFieldInterface := rightField.Interface()
 
+		if len(config.Filters) > 0 && config.isFiltered(FilterContext{
+			Path: path.with(step), ParentType: typ, Field: new(field), Index: -1,
+			Left: leftFieldInterface, Right: rightFieldInterface,
+		}) {
+			continue
+		}
+
+		if tag.omitEmptyEqual && isEmptyValue(leftField) && isEmptyValue(rightField) {
+			continue
+		}
+
+		config := config
+		if tag.adjustsConfig() {
+			config = config.fieldConfig(tag)
+		}
+
+		if tag.redact || tag.shallow || tag.comparator != "" {
+			err := compareFieldAsWhole(path.with(step), tag, leftFieldInterface, rightFieldInterface, result, config)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.json {
+			if err := compareJSONField(path.with(step), leftField, rightField, result, config); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if comparator != nil || handler != nil {
+			if err := compareValues(path.with(step), leftFieldInterface, rightFieldInterface, result, config); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if field.Type.Kind() == reflect.Slice {
-			modifiedConfig := config
-
-			if hasDiffTag(diffTag, "ignoreOrder") {
-				modifiedConfig = &CompareConfig{
-					IgnoreFields:         config.IgnoreFields,
-					IgnoreSliceOrder:     true,
-					CompareNumericValues: config.CompareNumericValues,
-					CustomComparators:    config.CustomComparators,
-					TypeHandlers:         config.TypeHandlers,
-					visitedPairs:         config.visitedPairs,
-					ignoreFieldsSet:      config.ignoreFieldsSet,
-					MaxDepth:             config.MaxDepth,
-					currentDepth:         config.currentDepth,
+			// keys configured by option take precedence over the key tag
+			if tag.key != "" && config.sliceKeyFor(path.with(step)) == nil {
+				err := compareSlicesByKey(path.with(step), leftField, rightField, &sliceKey{name: tag.key, field: tag.key}, result, config)
+				if err != nil {
+					return err
 				}
+				continue
 			}
 
-			err := compareSlices(fieldPath, leftField, rightField, result, modifiedConfig)
+			err := compareSlices(path.with(step), leftField, rightField, result, config)
 			if err != nil {
 				return err
 			}
 		} else {
 			if !reflect.DeepEqual(leftFieldInterface, rightFieldInterface) {
+				fieldPath := path.with(step)
 				leftKind := leftField.Kind()
+				if config.relaxesBasicValues() && isBasicKind(leftKind) && basicValuesEqual(leftFieldInterface, rightFieldInterface, config) {
+					continue
+				}
 				if leftKind == reflect.Pointer || leftKind == reflect.Struct ||
-					leftKind == reflect.Map || leftKind == reflect.Interface {
+					leftKind == reflect.Map || leftKind == reflect.Interface || leftKind == reflect.Slice ||
+					(leftKind == reflect.Array && config.relaxesBasicValues()) {
 					err := compareValues(fieldPath, leftFieldInterface, rightFieldInterface, result, config)
 					if err != nil {
 						return err
 					}
 				} else {
-					result.Diffs = append(result.Diffs, &StructDiff{
-						Diff: Diff{
-							Path:  fieldPath,
-							Left:  leftFieldInterface,
-							Right: rightFieldInterface,
-						},
-						FieldName:  field.Name,
-						ChangeType: ChangeTypeUpdated,
-					})
+					result.addStructDiff(fieldPath, leftFieldInterface, rightFieldInterface, changeTypeFor(leftFieldInterface, rightFieldInterface))
+					config.attachStringDiff(result)
 				}
 			}
 		}
@@ -344,17 +823,130 @@ func compareStructs(path string, leftVal, rightVal reflect.Value, result *DiffRe
 	return nil
 }
 
+// basicFieldsEqual reports whether two struct fields of the same type hold equal values of
+// a basic kind, without boxing them
+func basicFieldsEqual(left, right reflect.Value) bool {
+	switch {
+	case left.Kind() == reflect.String:
+		return left.String() == right.String()
+	case left.Kind() == reflect.Bool:
+		return left.Bool() == right.Bool()
+	case isSignedIntKind(left.Kind()):
+		return left.Int() == right.Int()
+	case isUnsignedIntKind(left.Kind()):
+		return left.Uint() == right.Uint()
+	case left.Kind() == reflect.Float32 || left.Kind() == reflect.Float64:
+		return left.Float() == right.Float()
+	}
+	return false
+}
+
+// readsUnexported reports whether the unexported fields of a struct type are compared
+func (c *CompareConfig) readsUnexported(typ reflect.Type) bool {
+	return c.UnexportedFields || c.UnexportedTypes[typ]
+}
+
+// exposeField returns an accessible view of an unexported field of an addressable struct
+func exposeField(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// compareFieldAsWhole compares a struct field as a single value, using the comparator named
+// by its tag, reflect.DeepEqual for shallow fields or a full comparison otherwise, and records
+// at most one change for it, with the values replaced by Redacted for redacted fields
+func compareFieldAsWhole(fieldPath Path, tag *fieldTag, left, right any, result *DiffResult, config *CompareConfig) error {
+	var equal bool
+	switch {
+	case tag.comparator != "":
+		comparator, ok := config.NamedComparators[tag.comparator]
+		if !ok {
+			return fmt.Errorf("no comparator registered as %q for field %s", tag.comparator, fieldPath)
+		}
+		var err error
+		if equal, err = comparator(left, right, config); err != nil {
+			return err
+		}
+	case tag.shallow:
+		equal = basicValuesEqual(left, right, config)
+	default:
+		var err error
+		if equal, err = valuesEqual(fieldPath, left, right, config); err != nil {
+			return err
+		}
+	}
+	if equal {
+		return nil
+	}
+
+	changeType := changeTypeFor(left, right)
+	if tag.redact {
+		if !isNilValue(left) {
+			left = Redacted
+		}
+		if !isNilValue(right) {
+			right = Redacted
+		}
+	}
+	result.addStructDiff(fieldPath, left, right, changeType)
+	return nil
+}
+
 // compareSlices compares two slices using appropriate algorithm based on configuration
-func compareSlices(path string, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+func compareSlices(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+	if key := config.sliceKeyFor(path); key != nil && leftVal.Kind() == reflect.Slice {
+		return compareSlicesByKey(path, leftVal, rightVal, key, result, config)
+	}
 	if config.IgnoreSliceOrder {
+		if len(config.Filters) > 0 {
+			leftVal, rightVal = config.unfilteredElements(path, leftVal, rightVal)
+		}
+		if config.relaxesBasicValues() && leftVal.Type() == rightVal.Type() {
+			return compareSlicesMatching(path, leftVal, rightVal, result, func(left, right any) bool {
+				equal, err := elementsEqual(path, -1, left, right, config)
+				return err == nil && equal
+			})
+		}
 		return compareSlicesAdvanced(path, leftVal, rightVal, result)
 	}
+	if config.SliceAlgorithm == SliceLCS || config.DetectMoves {
+		return compareSlicesLCS(path, leftVal, rightVal, result, config)
+	}
+	return compareSlicesPositional(path, leftVal, rightVal, result, config)
+}
+
+// unfilteredElements returns the elements of two slices the Filters accept, for comparisons
+// ignoring order: every element is described as present on its own side only
+func (c *CompareConfig) unfilteredElements(path Path, leftVal, rightVal reflect.Value) (reflect.Value, reflect.Value) {
+	sliceType := reflect.SliceOf(leftVal.Type().Elem())
+	keep := func(v reflect.Value, left bool) reflect.Value {
+		kept := reflect.MakeSlice(sliceType, 0, v.Len())
+		for i := range v.Len() {
+			var leftElem, rightElem any
+			if left {
+				leftElem = v.Index(i).Interface()
+			} else {
+				rightElem = v.Index(i).Interface()
+			}
+			if !c.skipsElement(path, leftVal.Type(), i, leftElem, rightElem) {
+				kept = reflect.Append(kept, v.Index(i))
+			}
+		}
+		return kept
+	}
+	return keep(leftVal, true), keep(rightVal, false)
+}
 
+// compareSlicesPositional compares the elements at equal indexes of two slices, reporting
+// trailing elements as added or removed
+func compareSlicesPositional(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
 	leftLen := leftVal.Len()
 	rightLen := rightVal.Len()
 	maxLen := max(rightLen, leftLen)
 
 	for i := range maxLen {
+		if result.limitReached() {
+			return result.truncate(path.index(i))
+		}
 		var leftElem, rightElem any
 		var hasLeftElem, hasRightElem bool
 
@@ -367,66 +959,339 @@ func compareSlices(path string, leftVal, rightVal reflect.Value, result *DiffRes
 			hasRightElem = true
 		}
 
+		if config.onlyMatcher != nil && config.isOutsideOnlyFields(path.index(i)) {
+			continue
+		}
+		if config.skipsElement(path, leftVal.Type(), i, leftElem, rightElem) {
+			continue
+		}
+
 		if hasLeftElem && hasRightElem {
 			leftElemVal := reflect.ValueOf(leftElem)
 			if leftElem == nil || rightElem == nil {
-				if !reflect.DeepEqual(leftElem, rightElem) {
-					result.Diffs = append(result.Diffs, &SliceDiff{
-						Diff: Diff{
-							Path:  path,
-							Left:  leftElem,
-							Right: rightElem,
-						},
-						Index:      i,
-						ChangeType: ChangeTypeUpdated,
-					})
+				if !reflect.DeepEqual(leftElem, rightElem) && !config.equalsAbsent(reflect.ValueOf(leftElem), reflect.ValueOf(rightElem)) {
+					result.addSliceDiff(path, i, leftElem, rightElem, ChangeTypeUpdated)
+				}
+			} else if leftElemVal.IsValid() && isBasicKind(leftElemVal.Kind()) {
+				if !basicValuesEqual(leftElem, rightElem, config) {
+					result.addSliceDiff(path, i, leftElem, rightElem, ChangeTypeUpdated)
+					config.attachStringDiff(result)
 				}
-			} else if leftElemVal.IsValid() && isBasicKind(leftElemVal.Kind()) && !reflect.DeepEqual(leftElem, rightElem) {
-				result.Diffs = append(result.Diffs, &SliceDiff{
-					Diff: Diff{
-						Path:  path,
-						Left:  leftElem,
-						Right: rightElem,
-					},
-					Index:      i,
-					ChangeType: ChangeTypeUpdated,
-				})
 			} else {
-				elementPath := path + "[" + itoa(i) + "]"
-				err := compareValues(elementPath, leftElem, rightElem, result, config)
+				err := compareValues(path.index(i), leftElem, rightElem, result, config)
 				if err != nil {
 					return err
 				}
 			}
 		} else if hasLeftElem {
 			// removed
-			result.Diffs = append(result.Diffs, &SliceDiff{
-				Diff: Diff{
-					Path:  path,
-					Left:  leftElem,
-					Right: nil,
-				},
-				Index:      i,
-				ChangeType: ChangeTypeRemoved,
-			})
+			result.addSliceDiff(path, i, leftElem, nil, ChangeTypeRemoved)
 		} else if hasRightElem {
 			// added
-			result.Diffs = append(result.Diffs, &SliceDiff{
-				Diff: Diff{
-					Path:  path,
-					Left:  nil,
-					Right: rightElem,
-				},
-				Index:      i,
-				ChangeType: ChangeTypeAdded,
-			})
+			result.addSliceDiff(path, i, nil, rightElem, ChangeTypeAdded)
 		}
 	}
 	return nil
 }
 
+// compareSlicesByKey pairs the elements of two slices by their identity key and compares
+// each pair recursively under an identity path step. Elements only present on one side,
+// or without a key, are reported as removed (at their left index) or added (at their
+// right index).
+func compareSlicesByKey(path Path, leftVal, rightVal reflect.Value, key *sliceKey, result *DiffResult, config *CompareConfig) error {
+	rightByKey := make(map[any][]int, rightVal.Len())
+	for j := range rightVal.Len() {
+		if k, ok := key.of(rightVal.Index(j)); ok {
+			rightByKey[k] = append(rightByKey[k], j)
+		}
+	}
+
+	matched := make([]bool, rightVal.Len())
+	pairedWith := make([]int, leftVal.Len())
+	for i := range leftVal.Len() {
+		pairedWith[i] = -1
+		if k, ok := key.of(leftVal.Index(i)); ok && len(rightByKey[k]) > 0 {
+			pairedWith[i] = rightByKey[k][0]
+			rightByKey[k] = rightByKey[k][1:]
+			matched[pairedWith[i]] = true
+		}
+	}
+
+	// elements rejected by the Filters are skipped, pairs take no part in move detection
+	var skipped, skippedRight []bool
+	if len(config.Filters) > 0 {
+		skipped, skippedRight = make([]bool, leftVal.Len()), make([]bool, rightVal.Len())
+		for i := range leftVal.Len() {
+			ctx := keyedFilterContext(path, leftVal, rightVal, key, i, pairedWith[i])
+			if skipped[i] = config.isFiltered(ctx); skipped[i] {
+				pairedWith[i] = -1
+			}
+		}
+		for j := range rightVal.Len() {
+			if !matched[j] {
+				skippedRight[j] = config.isFiltered(keyedFilterContext(path, leftVal, rightVal, key, -1, j))
+			}
+		}
+	}
+
+	// with move detection, pairs outside the longest run of pairs in unchanged
+	// relative order are moves
+	var kept map[int]bool
+	if config.DetectMoves {
+		kept = make(map[int]bool)
+		for _, i := range longestIncreasingRun(pairedWith) {
+			kept[i] = true
+		}
+	}
+
+	// elements paired by key give one change each, so the left elements are taken to
+	// hold most of the work
+	for i := range leftVal.Len() {
+		if result.limitReached() {
+			return result.truncateWithin(path, i, leftVal.Len())
+		}
+		if skipped != nil && skipped[i] {
+			continue
+		}
+		leftElem := leftVal.Index(i).Interface()
+		k, _ := key.of(leftVal.Index(i))
+		j := pairedWith[i]
+		if j < 0 {
+			result.addKeyedSliceDiff(path, key.name, k, i, -1, leftElem, nil, ChangeTypeRemoved)
+			continue
+		}
+		rightElem := rightVal.Index(j).Interface()
+		if kept != nil && !kept[i] {
+			result.addKeyedSliceDiff(path, key.name, k, i, j, leftElem, rightElem, ChangeTypeMoved)
+		}
+		if err := compareValues(path.identity(key.name, k, j), leftElem, rightElem, result, config); err != nil {
+			return err
+		}
+	}
+
+	for j := range rightVal.Len() {
+		if result.limitReached() {
+			return result.truncateWithin(path, leftVal.Len()+j, leftVal.Len()+rightVal.Len())
+		}
+		if !matched[j] && (skippedRight == nil || !skippedRight[j]) {
+			k, _ := key.of(rightVal.Index(j))
+			result.addKeyedSliceDiff(path, key.name, k, -1, j, nil, rightVal.Index(j).Interface(), ChangeTypeAdded)
+		}
+	}
+	return nil
+}
+
+// keyedFilterContext describes the element at index i of the left slice, paired with the
+// element at index j of the right slice, for the Filters. Either index is -1 if the element
+// is missing on that side.
+func keyedFilterContext(path Path, leftVal, rightVal reflect.Value, key *sliceKey, i, j int) FilterContext {
+	ctx := FilterContext{ParentType: leftVal.Type(), Index: j}
+	elem := reflect.Value{}
+	if j >= 0 {
+		elem = rightVal.Index(j)
+		ctx.Right = elem.Interface()
+	}
+	if i >= 0 {
+		elem = leftVal.Index(i)
+		ctx.Left = elem.Interface()
+		if j < 0 {
+			ctx.Index = i
+		}
+	}
+	ctx.Path = path.index(ctx.Index)
+	if k, ok := key.of(elem); ok {
+		ctx.Path = path.identity(key.name, k, ctx.Index)
+	}
+	return ctx
+}
+
+// longestIncreasingRun returns the positions of a longest strictly increasing
+// subsequence of the non-negative values in seq
+func longestIncreasingRun(seq []int) []int {
+	var tails []int // tails[l] is the position ending the best run of length l+1
+	prev := make([]int, len(seq))
+	for i, v := range seq {
+		if v < 0 {
+			continue
+		}
+		l, _ := slices.BinarySearchFunc(tails, v, func(pos, target int) int { return seq[pos] - target })
+		if l > 0 {
+			prev[i] = tails[l-1]
+		} else {
+			prev[i] = -1
+		}
+		if l == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[l] = i
+		}
+	}
+
+	run := make([]int, len(tails))
+	for k, i := len(tails)-1, -1; k >= 0; k-- {
+		if i < 0 {
+			i = tails[k]
+		} else {
+			i = prev[i]
+		}
+		run[k] = i
+	}
+	return run
+}
+
+// compareSlicesLCS aligns two slices along their longest common subsequence. Between two
+// common elements, deleted and inserted elements are paired up and compared as updates;
+// the remaining ones are reported as removed (at their left index) or added (at their
+// right index). Updates and nested diffs use the right index.
+//
+// Every two deletions and insertions yield at least one change unless the Filters skip
+// elements, so if the alignment needs more than twice the changes the result still has room
+// for, the result is truncated in any case and the slices are compared positionally instead
+// of searching further.
+func compareSlicesLCS(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+	leftElems, rightElems := sliceElements(leftVal), sliceElements(rightVal)
+	var equalErr error
+	equal := func(i, j int) bool {
+		if equalErr != nil {
+			return false
+		}
+		eq, err := elementsEqual(path, j, leftElems[i], rightElems[j], config)
+		if err != nil {
+			equalErr = err
+		}
+		return eq
+	}
+	maxEdits := -1
+	if remaining, limited := result.remaining(); limited && len(config.Filters) == 0 {
+		maxEdits = 2 * remaining
+	}
+	edits, aligned := boundedEdits(leftVal.Len(), rightVal.Len(), maxEdits, equal)
+	if equalErr != nil {
+		return equalErr
+	}
+	if !aligned {
+		return compareSlicesPositional(path, leftVal, rightVal, result, config)
+	}
+
+	// with move detection, deleted elements equal to an inserted element are moves
+	var movedFrom map[int]bool
+	movedTo := make(map[int]int)
+	if config.DetectMoves {
+		movedFrom = make(map[int]bool)
+		for _, d := range edits {
+			if d.op != editDelete {
+				continue
+			}
+			for _, e := range edits {
+				if _, taken := movedTo[e.right]; e.op == editInsert && !taken && equal(d.left, e.right) {
+					movedFrom[d.left] = true
+					movedTo[e.right] = d.left
+					break
+				}
+			}
+		}
+	}
+	if equalErr != nil {
+		return equalErr
+	}
+
+	var deleted, inserted []int
+	flush := func() error {
+		paired := min(len(deleted), len(inserted))
+		for k := range paired {
+			if result.limitReached() {
+				return result.truncate(path.index(deleted[k]))
+			}
+			from, to := deleted[k], inserted[k]
+			leftElem, rightElem := leftElems[from], rightElems[to]
+			if config.skipsElement(path, leftVal.Type(), to, leftElem, rightElem) {
+				continue
+			}
+			leftElemVal := reflect.ValueOf(leftElem)
+			if leftElem == nil || rightElem == nil || isBasicKind(leftElemVal.Kind()) {
+				result.addSliceEdit(path, from, to, leftElem, rightElem, ChangeTypeUpdated)
+				config.attachStringDiff(result)
+				continue
+			}
+			if err := compareValues(path.index(to), leftElem, rightElem, result, config); err != nil {
+				return err
+			}
+		}
+		for _, from := range deleted[paired:] {
+			if result.limitReached() {
+				return result.truncate(path.index(from))
+			}
+			if !config.skipsElement(path, leftVal.Type(), from, leftElems[from], nil) {
+				result.addSliceEdit(path, from, -1, leftElems[from], nil, ChangeTypeRemoved)
+			}
+		}
+		for _, to := range inserted[paired:] {
+			if result.limitReached() {
+				return result.truncate(path.index(to))
+			}
+			if !config.skipsElement(path, leftVal.Type(), to, nil, rightElems[to]) {
+				result.addSliceEdit(path, -1, to, nil, rightElems[to], ChangeTypeAdded)
+			}
+		}
+		deleted, inserted = deleted[:0], inserted[:0]
+		return nil
+	}
+
+	for _, e := range edits {
+		if result.limitReached() {
+			return result.truncate(path.index(e.left))
+		}
+		switch {
+		case e.op == editDelete && movedFrom[e.left]:
+		case e.op == editDelete:
+			deleted = append(deleted, e.left)
+		case e.op == editInsert:
+			if from, moved := movedTo[e.right]; moved {
+				if !config.skipsElement(path, leftVal.Type(), e.right, leftElems[from], rightElems[e.right]) {
+					result.addSliceEdit(path, from, e.right, leftElems[from], rightElems[e.right], ChangeTypeMoved)
+				}
+				continue
+			}
+			inserted = append(inserted, e.right)
+		default:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// sliceElements returns the elements of a slice or array as interface values
+func sliceElements(v reflect.Value) []any {
+	elems := make([]any, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i).Interface()
+	}
+	return elems
+}
+
+// elementsEqual reports whether comparing two slice elements yields no differences. The
+// elements are compared at index of the slice at path, or at path itself if index is -1.
+func elementsEqual(path Path, index int, left, right any, config *CompareConfig) (bool, error) {
+	if config.CustomComparators == nil && reflect.DeepEqual(left, right) {
+		return true, nil
+	}
+	leftVal := reflect.ValueOf(left)
+	rightVal := reflect.ValueOf(right)
+	if leftVal.IsValid() && rightVal.IsValid() && leftVal.Type() == rightVal.Type() &&
+		isBasicKind(leftVal.Kind()) && config.CustomComparators == nil {
+		return basicValuesEqual(left, right, config), nil
+	}
+
+	if index >= 0 {
+		path = path.index(index)
+	}
+	return valuesEqual(path, left, right, config)
+}
+
 // compareSlicesAdvanced compares slices using ID-based matching or value-based matching
-func compareSlicesAdvanced(path string, leftVal, rightVal reflect.Value, result *DiffResult) error {
+func compareSlicesAdvanced(path Path, leftVal, rightVal reflect.Value, result *DiffResult) error {
 
 	if !leftVal.IsValid() && !rightVal.IsValid() {
 		return nil
@@ -434,30 +1299,18 @@ func compareSlicesAdvanced(path string, leftVal, rightVal reflect.Value, result
 
 	if !leftVal.IsValid() {
 		if rightVal.IsValid() {
-			result.Diffs = append(result.Diffs, &Diff{
-				Path:  path,
-				Left:  nil,
-				Right: rightVal.Interface(),
-			})
+			result.addDiff(path, nil, rightVal.Interface(), ChangeTypeAdded)
 		}
 		return nil
 	}
 
 	if !rightVal.IsValid() {
-		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  leftVal.Interface(),
-			Right: nil,
-		})
+		result.addDiff(path, leftVal.Interface(), nil, ChangeTypeRemoved)
 		return nil
 	}
 
 	if leftVal.Type() != rightVal.Type() {
-		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  leftVal.Interface(),
-			Right: rightVal.Interface(),
-		})
+		result.addDiff(path, leftVal.Interface(), rightVal.Interface(), ChangeTypeTypeChanged)
 		return nil
 	}
 
@@ -465,7 +1318,7 @@ func compareSlicesAdvanced(path string, leftVal, rightVal reflect.Value, result
 }
 
 // compareSlicesByValue compares slices using value-based matching (similar to the original ignoreOrder)
-func compareSlicesByValue(path string, leftVal, rightVal reflect.Value, result *DiffResult) error {
+func compareSlicesByValue(path Path, leftVal, rightVal reflect.Value, result *DiffResult) error {
 	elemType := leftVal.Type().Elem()
 	if !elemType.Comparable() {
 		return compareSlicesWithDeepEqual(path, leftVal, rightVal, result)
@@ -483,6 +1336,12 @@ func compareSlicesByValue(path string, leftVal, rightVal reflect.Value, result *
 		return compareSlicesWithDeepEqual(path, leftVal, rightVal, result)
 	}
 
+	if remaining, limited := result.remaining(); limited {
+		if found, err := probeSlicesByValue(path, leftVal, rightVal, remaining, result); found {
+			return err
+		}
+	}
+
 	leftCounts := make(map[any]int, leftLen)
 	rightCounts := make(map[any]int, rightLen)
 
@@ -497,34 +1356,38 @@ func compareSlicesByValue(path string, leftVal, rightVal reflect.Value, result *
 	}
 
 	maxDiffs := leftLen + rightLen
+	if remaining, limited := result.remaining(); limited {
+		maxDiffs = min(maxDiffs, remaining)
+	}
 	if cap(result.Diffs) < len(result.Diffs)+maxDiffs {
 		result.Diffs = slices.Grow(result.Diffs, maxDiffs)
 	}
 
 	// removed
+	done, steps := 0, len(leftCounts)+len(rightCounts)
 	for elem, leftCount := range leftCounts {
+		done++
 		rightCount := rightCounts[elem]
 		if leftCount > rightCount {
 			for j := 0; j < leftCount-rightCount; j++ {
-				result.Diffs = append(result.Diffs, &Diff{
-					Path:  path,
-					Left:  elem,
-					Right: nil,
-				})
+				if result.limitReached() {
+					return result.truncateWithin(path, done-1, steps)
+				}
+				result.addDiff(path, elem, nil, ChangeTypeRemoved)
 			}
 		}
 	}
 
 	// added
 	for elem, rightCount := range rightCounts {
+		done++
 		leftCount := leftCounts[elem]
 		if rightCount > leftCount {
 			for j := 0; j < rightCount-leftCount; j++ {
-				result.Diffs = append(result.Diffs, &Diff{
-					Path:  path,
-					Left:  nil,
-					Right: elem,
-				})
+				if result.limitReached() {
+					return result.truncateWithin(path, done-1, steps)
+				}
+				result.addDiff(path, nil, elem, ChangeTypeAdded)
 			}
 		}
 	}
@@ -532,6 +1395,59 @@ func compareSlicesByValue(path string, leftVal, rightVal reflect.Value, result *
 	return nil
 }
 
+// probeSlicesByValue looks for the remaining changes a limited comparison takes among the
+// first distinct values of each slice, counting only those values instead of all elements.
+// It reports false, recording nothing, if they hold fewer changes than that.
+func probeSlicesByValue(path Path, leftVal, rightVal reflect.Value, remaining int, result *DiffResult) (bool, error) {
+	if remaining == 0 {
+		return true, result.truncate(path)
+	}
+	// the balance of a value is its count in left minus its count in right
+	balance := make(map[any]int, 2*remaining)
+	sides := [2]reflect.Value{leftVal, rightVal}
+	for _, v := range sides {
+		for i, added := 0, 0; i < v.Len() && added < remaining; i++ {
+			elem := v.Index(i).Interface()
+			if _, seen := balance[elem]; !seen {
+				balance[elem] = 0
+				added++
+			}
+		}
+	}
+	covered := 0
+	for side, v := range sides {
+		for i := range v.Len() {
+			elem := v.Index(i).Interface()
+			if n, ok := balance[elem]; ok {
+				balance[elem] = n + 1 - 2*side
+				covered++
+			}
+		}
+	}
+	found := 0
+	for _, n := range balance {
+		found += max(n, -n)
+	}
+	if found <= remaining {
+		// the changes may all fit within the limit
+		return false, nil
+	}
+
+	result.Diffs = slices.Grow(result.Diffs, remaining)
+	for elem, n := range balance {
+		for ; n > 0 && !result.limitReached(); n-- {
+			result.addDiff(path, elem, nil, ChangeTypeRemoved)
+		}
+	}
+	for elem, n := range balance {
+		for ; n < 0 && !result.limitReached(); n++ {
+			result.addDiff(path, nil, elem, ChangeTypeAdded)
+		}
+	}
+	// the recorded changes stand for their share of the elements of the probed values
+	return true, result.truncateWithin(path, covered*remaining/found, leftVal.Len()+rightVal.Len())
+}
+
 func sliceValuesAreHashSafe(val reflect.Value) bool {
 	if typeIsStaticallyHashSafe(val.Type().Elem()) {
 		return true
@@ -600,20 +1516,29 @@ func valueIsHashSafe(val reflect.Value) bool {
 
 // compareSlicesUnordered provides unified comparison for slices ignoring order
 // Uses DeepEqual for matching elements
-func compareSlicesUnordered(path string, leftVal, rightVal reflect.Value, result *DiffResult) error {
+func compareSlicesUnordered(path Path, leftVal, rightVal reflect.Value, result *DiffResult) error {
+	return compareSlicesMatching(path, leftVal, rightVal, result, reflect.DeepEqual)
+}
+
+// compareSlicesMatching compares slices ignoring order, pairing each left element with
+// the first unmatched right element that equal reports as equal
+func compareSlicesMatching(path Path, leftVal, rightVal reflect.Value, result *DiffResult, equal func(left, right any) bool) error {
 	leftLen := leftVal.Len()
 	rightLen := rightVal.Len()
 
 	rightMatched := make([]bool, rightLen)
 
 	for i := range leftLen {
+		if result.limitReached() {
+			return result.truncateWithin(path, i, leftLen+rightLen)
+		}
 		leftElem := leftVal.Index(i).Interface()
 		found := false
 
 		for j := range rightLen {
 			if !rightMatched[j] {
 				rightElem := rightVal.Index(j).Interface()
-				if reflect.DeepEqual(leftElem, rightElem) {
+				if equal(leftElem, rightElem) {
 					rightMatched[j] = true
 					found = true
 					break
@@ -622,23 +1547,18 @@ func compareSlicesUnordered(path string, leftVal, rightVal reflect.Value, result
 		}
 
 		if !found {
-			result.Diffs = append(result.Diffs, &Diff{
-				Path:  path,
-				Left:  leftElem,
-				Right: nil,
-			})
+			result.addDiff(path, leftElem, nil, ChangeTypeRemoved)
 		}
 	}
 
 	// Find unmatched right elements
 	for j := range rightLen {
+		if result.limitReached() {
+			return result.truncateWithin(path, leftLen+j, leftLen+rightLen)
+		}
 		if !rightMatched[j] {
 			rightElem := rightVal.Index(j).Interface()
-			result.Diffs = append(result.Diffs, &Diff{
-				Path:  path,
-				Left:  nil,
-				Right: rightElem,
-			})
+			result.addDiff(path, nil, rightElem, ChangeTypeAdded)
 		}
 	}
 
@@ -646,15 +1566,41 @@ func compareSlicesUnordered(path string, leftVal, rightVal reflect.Value, result
 }
 
 // compareSlicesSimple provides optimized comparison for small slices
-func compareSlicesSimple(path string, leftVal, rightVal reflect.Value, result *DiffResult) error {
+func compareSlicesSimple(path Path, leftVal, rightVal reflect.Value, result *DiffResult) error {
 	return compareSlicesUnordered(path, leftVal, rightVal, result)
 }
 
 // compareSlicesWithDeepEqual compares slices using DeepEqual for non-comparable types, ignoring order
-func compareSlicesWithDeepEqual(path string, leftVal, rightVal reflect.Value, result *DiffResult) error {
+func compareSlicesWithDeepEqual(path Path, leftVal, rightVal reflect.Value, result *DiffResult) error {
 	return compareSlicesUnordered(path, leftVal, rightVal, result)
 }
 
+// changeTypeFor classifies a change by whether either side is nil
+func changeTypeFor(left, right any) ChangeType {
+	switch {
+	case isNilValue(left):
+		return ChangeTypeAdded
+	case isNilValue(right):
+		return ChangeTypeRemoved
+	default:
+		return ChangeTypeUpdated
+	}
+}
+
+// isNilValue reports whether v is nil or holds a nil pointer, interface, map, slice, func or channel
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
 // isBasicKind returns true if the kind is a basic comparable type (numeric, bool, or string)
 func isBasicKind(k reflect.Kind) bool {
 	return k <= reflect.Complex128 || k == reflect.String
@@ -742,6 +1688,97 @@ func numericValuesEqual(leftVal, rightVal reflect.Value) bool {
 	return false
 }
 
+// hasFloatTolerance reports whether floats are compared with a tolerance
+func (c *CompareConfig) hasFloatTolerance() bool {
+	return c.FloatAbsTolerance > 0 || c.FloatRelTolerance > 0
+}
+
+// relaxesBasicValues reports whether values of basic kinds are compared by something
+// other than plain equality
+func (c *CompareConfig) relaxesBasicValues() bool {
+	return c.hasFloatTolerance() || c.ignoreCase || c.ignoreWhitespace
+}
+
+// floatsWithinTolerance reports whether two floats differ by at most the configured
+// absolute tolerance or relative tolerance. NaN is never within tolerance.
+func floatsWithinTolerance(a, b float64, config *CompareConfig) bool {
+	if a == b {
+		return true
+	}
+	diff := math.Abs(a - b)
+	return diff <= config.FloatAbsTolerance ||
+		diff <= config.FloatRelTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// basicValuesEqual compares two values of a basic kind, applying the float tolerance
+// to float and complex values and the case and whitespace rules to strings of the same type
+func basicValuesEqual(left, right any, config *CompareConfig) bool {
+	if config.relaxesBasicValues() {
+		leftVal := reflect.ValueOf(left)
+		rightVal := reflect.ValueOf(right)
+		if leftVal.IsValid() && rightVal.IsValid() && leftVal.Type() == rightVal.Type() {
+			switch kind := leftVal.Kind(); {
+			case isFloatKind(kind):
+				return floatsWithinTolerance(leftVal.Float(), rightVal.Float(), config)
+			case kind == reflect.Complex64 || kind == reflect.Complex128:
+				l, r := leftVal.Complex(), rightVal.Complex()
+				return floatsWithinTolerance(real(l), real(r), config) && floatsWithinTolerance(imag(l), imag(r), config)
+			case kind == reflect.String:
+				return stringsEqual(leftVal.String(), rightVal.String(), config)
+			}
+		}
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// stringsEqual compares two strings, ignoring case and whitespace if configured
+func stringsEqual(left, right string, config *CompareConfig) bool {
+	if config.ignoreWhitespace {
+		left, right = removeWhitespace(left), removeWhitespace(right)
+	}
+	if config.ignoreCase {
+		return strings.EqualFold(left, right)
+	}
+	return left == right
+}
+
+// removeWhitespace returns s without any Unicode white space
+func removeWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// numericValuesWithinTolerance compares numeric values across different types, applying
+// the float tolerance when either value is a float
+func numericValuesWithinTolerance(leftVal, rightVal reflect.Value, config *CompareConfig) bool {
+	if config.hasFloatTolerance() && (isFloatKind(leftVal.Kind()) || isFloatKind(rightVal.Kind())) &&
+		!isComplexKind(leftVal.Kind()) && !isComplexKind(rightVal.Kind()) {
+		return floatsWithinTolerance(toFloat64(leftVal), toFloat64(rightVal), config)
+	}
+	return numericValuesEqual(leftVal, rightVal)
+}
+
+// isComplexKind returns true if the kind is a complex number type
+func isComplexKind(k reflect.Kind) bool {
+	return k == reflect.Complex64 || k == reflect.Complex128
+}
+
+// toFloat64 converts an integer or float value to float64
+func toFloat64(v reflect.Value) float64 {
+	switch {
+	case isSignedIntKind(v.Kind()):
+		return float64(v.Int())
+	case isUnsignedIntKind(v.Kind()):
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
 // isSignedIntKind returns true if the kind is a signed integer
 func isSignedIntKind(k reflect.Kind) bool {
 	return k >= reflect.Int && k <= reflect.Int64
@@ -752,30 +1789,29 @@ func isUnsignedIntKind(k reflect.Kind) bool {
 	return k >= reflect.Uint && k <= reflect.Uintptr
 }
 
-// compareMaps compares two maps key by keywithout fmt
-func itoa(i int) string {
-	return strconv.Itoa(i)
-}
-
 // compareMaps compares two maps key by key
-func compareMaps(path string, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
-	for _, key := range leftVal.MapKeys() {
-		keyStr := fmt.Sprintf("%v", key.Interface())
-		elementPath := path + "[" + keyStr + "]"
-
+func compareMaps(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+	// entries present on both sides give one change each, so the left entries are taken
+	// to hold most of the work
+	for i, key := range leftVal.MapKeys() {
+		if result.limitReached() {
+			return result.truncateWithin(path, i, leftVal.Len())
+		}
+		if (len(config.IgnoreFields) > 0 && config.isPathIgnored(path.key(key.Interface()))) ||
+			config.onlyMatcher != nil && config.isOutsideOnlyFields(path.key(key.Interface())) {
+			continue
+		}
 		rightMapVal := rightVal.MapIndex(key)
 		leftMapVal := leftVal.MapIndex(key)
+		if len(config.Filters) > 0 && config.isFiltered(mapFilterContext(path, leftVal, key, leftMapVal, rightMapVal)) {
+			continue
+		}
 		if !rightMapVal.IsValid() {
+			if config.equalsAbsent(leftMapVal, rightMapVal) {
+				continue
+			}
 			// Key removed
-			result.Diffs = append(result.Diffs, &MapDiff{
-				Diff: Diff{
-					Path:  elementPath,
-					Left:  leftMapVal.Interface(),
-					Right: nil,
-				},
-				Key:        key.Interface(),
-				ChangeType: ChangeTypeRemoved,
-			})
+			result.addMapDiff(path.key(key.Interface()), key.Interface(), leftMapVal.Interface(), nil, ChangeTypeRemoved)
 			continue
 		}
 
@@ -786,16 +1822,8 @@ func compareMaps(path string, leftVal, rightVal reflect.Value, result *DiffResul
 		rightValReflect := reflect.ValueOf(rightInterface)
 
 		if !leftValReflect.IsValid() || !rightValReflect.IsValid() {
-			if !reflect.DeepEqual(leftInterface, rightInterface) {
-				result.Diffs = append(result.Diffs, &MapDiff{
-					Diff: Diff{
-						Path:  elementPath,
-						Left:  leftInterface,
-						Right: rightInterface,
-					},
-					Key:        key.Interface(),
-					ChangeType: ChangeTypeUpdated,
-				})
+			if !reflect.DeepEqual(leftInterface, rightInterface) && !config.equalsAbsent(leftValReflect, rightValReflect) {
+				result.addMapDiff(path.key(key.Interface()), key.Interface(), leftInterface, rightInterface, ChangeTypeUpdated)
 			}
 			continue
 		}
@@ -803,45 +1831,22 @@ func compareMaps(path string, leftVal, rightVal reflect.Value, result *DiffResul
 		// Check for type mismatch with potential numeric comparison
 		if leftValReflect.Type() != rightValReflect.Type() {
 			if config.CompareNumericValues && isNumericKind(leftValReflect.Kind()) && isNumericKind(rightValReflect.Kind()) {
-				if !numericValuesEqual(leftValReflect, rightValReflect) {
-					result.Diffs = append(result.Diffs, &MapDiff{
-						Diff: Diff{
-							Path:  elementPath,
-							Left:  leftInterface,
-							Right: rightInterface,
-						},
-						Key:        key.Interface(),
-						ChangeType: ChangeTypeUpdated,
-					})
+				if !numericValuesWithinTolerance(leftValReflect, rightValReflect, config) {
+					result.addMapDiff(path.key(key.Interface()), key.Interface(), leftInterface, rightInterface, ChangeTypeUpdated)
 				}
 			} else {
-				result.Diffs = append(result.Diffs, &MapDiff{
-					Diff: Diff{
-						Path:  elementPath,
-						Left:  leftInterface,
-						Right: rightInterface,
-					},
-					Key:        key.Interface(),
-					ChangeType: ChangeTypeUpdated,
-				})
+				result.addMapDiff(path.key(key.Interface()), key.Interface(), leftInterface, rightInterface, ChangeTypeTypeChanged)
 			}
 			continue
 		}
 
 		if isBasicKind(leftValReflect.Kind()) {
-			if !reflect.DeepEqual(leftInterface, rightInterface) {
-				result.Diffs = append(result.Diffs, &MapDiff{
-					Diff: Diff{
-						Path:  elementPath,
-						Left:  leftInterface,
-						Right: rightInterface,
-					},
-					Key:        key.Interface(),
-					ChangeType: ChangeTypeUpdated,
-				})
+			if !basicValuesEqual(leftInterface, rightInterface, config) {
+				result.addMapDiff(path.key(key.Interface()), key.Interface(), leftInterface, rightInterface, ChangeTypeUpdated)
+				config.attachStringDiff(result)
 			}
 		} else {
-			err := compareValues(elementPath, leftInterface, rightInterface, result, config)
+			err := compareValues(path.key(key.Interface()), leftInterface, rightInterface, result, config)
 			if err != nil {
 				return err
 			}
@@ -849,28 +1854,38 @@ func compareMaps(path string, leftVal, rightVal reflect.Value, result *DiffResul
 	}
 
 	// added
-	for _, key := range rightVal.MapKeys() {
+	for j, key := range rightVal.MapKeys() {
+		if result.limitReached() {
+			return result.truncateWithin(path, leftVal.Len()+j, leftVal.Len()+rightVal.Len())
+		}
 		if !leftVal.MapIndex(key).IsValid() {
-			keyStr := fmt.Sprintf("%v", key.Interface())
-			elementPath := path + "[" + keyStr + "]"
-
-			result.Diffs = append(result.Diffs, &MapDiff{
-				Diff: Diff{
-					Path:  elementPath,
-					Left:  nil,
-					Right: rightVal.MapIndex(key).Interface(),
-				},
-				Key:        key.Interface(),
-				ChangeType: ChangeTypeAdded,
-			})
+			if config.equalsAbsent(reflect.Value{}, rightVal.MapIndex(key)) ||
+				(len(config.IgnoreFields) > 0 && config.isPathIgnored(path.key(key.Interface()))) ||
+				config.onlyMatcher != nil && config.isOutsideOnlyFields(path.key(key.Interface())) ||
+				len(config.Filters) > 0 && config.isFiltered(mapFilterContext(path, rightVal, key, reflect.Value{}, rightVal.MapIndex(key))) {
+				continue
+			}
+			result.addMapDiff(path.key(key.Interface()), key.Interface(), nil, rightVal.MapIndex(key).Interface(), ChangeTypeAdded)
 		}
 	}
 
 	return nil
 }
 
+// mapFilterContext describes a map entry for the Filters; invalid values are passed as nil
+func mapFilterContext(path Path, mapVal, key, leftVal, rightVal reflect.Value) FilterContext {
+	ctx := FilterContext{Path: path.key(key.Interface()), ParentType: mapVal.Type(), MapKey: key.Interface(), Index: -1}
+	if leftVal.IsValid() {
+		ctx.Left = leftVal.Interface()
+	}
+	if rightVal.IsValid() {
+		ctx.Right = rightVal.Interface()
+	}
+	return ctx
+}
+
 // comparePointers compares two pointers by dereferencing them
-func comparePointers(path string, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+func comparePointers(path Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
 	if leftVal.IsNil() && rightVal.IsNil() {
 		return nil
 	}
@@ -892,22 +1907,8 @@ func comparePointers(path string, leftVal, rightVal reflect.Value, result *DiffR
 	}
 
 	config.visitedPairs[pairKey] = true
-	err := compareValues(path, leftVal.Elem().Interface(), rightVal.Elem().Interface(), result, config)
+	err := compareValues(path.with(PathStep{Kind: StepPointer}), leftVal.Elem().Interface(), rightVal.Elem().Interface(), result, config)
 	delete(config.visitedPairs, pairKey)
 
 	return err
 }
-
-// hasDiffTag checks if the diff tag contains an exact match for the given tag value
-func hasDiffTag(diffTag, tagValue string) bool {
-	if diffTag == "" {
-		return false
-	}
-	tags := strings.SplitSeq(diffTag, ",")
-	for tag := range tags {
-		if strings.TrimSpace(tag) == tagValue {
-			return true
-		}
-	}
-	return false
-}
@@ -1,8 +1,18 @@
 package godiff
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -491,3 +501,870 @@ func TestIsFieldIgnoredEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestFloatTolerance(t *testing.T) {
+	type Measurement struct {
+		Value   float64
+		Samples []float64
+		Limits  map[string]float32
+		Signal  complex128
+		Exact   float64
+		Loose   float64 `diff:"tolerance=0.5"`
+	}
+
+	left := Measurement{
+		Value:   1.0,
+		Samples: []float64{1.0, 2.0},
+		Limits:  map[string]float32{"max": 10},
+		Signal:  complex(1, 1),
+		Loose:   1,
+	}
+	right := Measurement{
+		Value:   1.0005,
+		Samples: []float64{1.0004, 2.0},
+		Limits:  map[string]float32{"max": 10.0009},
+		Signal:  complex(1.0001, 0.9999),
+		Loose:   1.4,
+	}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 4 {
+		t.Errorf("Expected 4 differences without tolerance, got %d: %s", len(result.Diffs), result.String())
+	}
+
+	result, err = Compare(left, right, WithFloatTolerance(0.001, 0))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected no differences within tolerance, got %s", result.String())
+	}
+
+	right.Exact = 0.01
+	result, err = Compare(left, right, WithFloatTolerance(0.001, 0))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].base().Path != "Exact" {
+		t.Errorf("Expected a difference in Exact, got %s", result.String())
+	}
+
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		opts     []CompareOption
+		expected bool
+	}{
+		{"relative tolerance", 1000.0, 1001.0, []CompareOption{WithFloatTolerance(0, 0.01)}, true},
+		{"outside relative tolerance", 1.0, 1.1, []CompareOption{WithFloatTolerance(0, 0.01)}, false},
+		{"nan", math.NaN(), math.NaN(), []CompareOption{WithFloatTolerance(1, 1)}, false},
+		{"int and float", 1, 1.0001, []CompareOption{WithFloatTolerance(0.001, 0), WithCompareNumericValues()}, true},
+		{"unordered slice", []float64{1, 2}, []float64{2.0001, 0.9999}, []CompareOption{WithFloatTolerance(0.001, 0), WithIgnoreSliceOrder()}, true},
+		{"lcs slice", []float64{1, 2, 3}, []float64{1.0001, 3}, []CompareOption{WithFloatTolerance(0.001, 0), WithSliceAlgorithm(SliceLCS)}, false},
+		{"array", [2]float64{1, 2}, [2]float64{1, 2.0001}, []CompareOption{WithFloatTolerance(0.001, 0)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if result.HasDifferences() == tt.expected {
+				t.Errorf("Expected equal=%v, got %s", tt.expected, result.String())
+			}
+		})
+	}
+}
+
+func TestTimeTolerance(t *testing.T) {
+	type Event struct {
+		Name    string
+		At      time.Time
+		Created time.Time `diff:"truncate=1h"`
+		Updated time.Time `diff:"tolerance=1m"`
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	left := Event{Name: "deploy", At: base, Created: base, Updated: base}
+	right := Event{Name: "deploy", At: base.Add(500 * time.Millisecond), Created: base.Add(30 * time.Minute), Updated: base.Add(-45 * time.Second)}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].base().Path != "At" {
+		t.Errorf("Expected only At to differ, got %s", result.String())
+	}
+
+	result, err = Compare(left, right, WithTimeTolerance(time.Second))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected no differences within time tolerance, got %s", result.String())
+	}
+
+	result, err = Compare(left, right, WithTimeTruncate(time.Second))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected no differences after truncation, got %s", result.String())
+	}
+
+	right.At = base.Add(2 * time.Second)
+	result, err = Compare(left, right, WithTimeTolerance(time.Second))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].RightValue() != right.At {
+		t.Errorf("Expected the original time in the difference, got %s", result.String())
+	}
+
+	type Invalid struct {
+		Value float64 `diff:"tolerance=abc"`
+	}
+	if _, err := Compare(Invalid{Value: 1}, Invalid{Value: 2}); err == nil {
+		t.Error("Expected error for invalid tolerance tag")
+	}
+}
+
+func TestIgnoreFieldPatterns(t *testing.T) {
+	type Item struct {
+		Name      string
+		UpdatedAt time.Time
+	}
+	type Record struct {
+		Items     []Item
+		Meta      map[string]string
+		UpdatedAt time.Time
+		Secret    string
+		Token     string
+	}
+
+	left := Record{
+		Items:     []Item{{Name: "a", UpdatedAt: time.Unix(1, 0)}},
+		Meta:      map[string]string{"env": "prod", "gone": "x"},
+		UpdatedAt: time.Unix(1, 0),
+		Secret:    "s1",
+		Token:     "t1",
+	}
+	right := Record{
+		Items:     []Item{{Name: "b", UpdatedAt: time.Unix(2, 0)}},
+		Meta:      map[string]string{"env": "dev", "new": "y"},
+		UpdatedAt: time.Unix(2, 0),
+		Secret:    "s2",
+		Token:     "t2",
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{"no patterns", nil, []string{"Items[0].Name", "Items[0].UpdatedAt", "Meta[env]", "Meta[gone]", "Meta[new]", "UpdatedAt", "Secret", "Token"}},
+		{"index wildcard", []string{"Items[*].UpdatedAt"}, []string{"Items[0].Name", "Meta[env]", "Meta[gone]", "Meta[new]", "UpdatedAt", "Secret", "Token"}},
+		{"map entries", []string{"Meta[*]"}, []string{"Items[0].Name", "Items[0].UpdatedAt", "UpdatedAt", "Secret", "Token"}},
+		{"any depth", []string{"**.UpdatedAt"}, []string{"Items[0].Name", "Meta[env]", "Meta[gone]", "Meta[new]", "Secret", "Token"}},
+		{"below a field", []string{"Items.**"}, []string{"Meta[env]", "Meta[gone]", "Meta[new]", "UpdatedAt", "Secret", "Token"}},
+		{"name wildcard", []string{"*At", "Meta[?e*]"}, []string{"Items[0].Name", "Items[0].UpdatedAt", "Meta[env]", "Meta[gone]", "Secret", "Token"}},
+		{"regex", []string{`re:^(Secret|Token)$`}, []string{"Items[0].Name", "Items[0].UpdatedAt", "Meta[env]", "Meta[gone]", "Meta[new]", "UpdatedAt"}},
+		{"regex on a nested name", []string{`re:^(Name|UpdatedAt)$`}, []string{"Items[0].Name", "Items[0].UpdatedAt", "Meta[env]", "Meta[gone]", "Meta[new]", "Secret", "Token"}},
+		{"plain nested name", []string{"Name"}, []string{"Items[0].UpdatedAt", "Meta[env]", "Meta[gone]", "Meta[new]", "UpdatedAt", "Secret", "Token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(left, right, WithIgnoreFields(tt.patterns...))
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			var paths []string
+			for _, diff := range result.Diffs {
+				paths = append(paths, diff.base().Path)
+			}
+			slices.Sort(paths)
+			expected := slices.Sorted(slices.Values(tt.expected))
+			if !slices.Equal(paths, expected) {
+				t.Errorf("Expected %v, got %v", expected, paths)
+			}
+		})
+	}
+
+	if _, err := Compare(left, right, WithIgnoreFields("re:(")); err == nil {
+		t.Error("Expected error for invalid regex pattern")
+	}
+	if _, err := Compare(left, right, WithIgnoreFields("Items[*")); err == nil {
+		t.Error("Expected error for invalid wildcard pattern")
+	}
+}
+
+func TestOnlyFields(t *testing.T) {
+	type Order struct {
+		ID    int
+		Total float64
+		Note  string
+	}
+	type User struct {
+		Name  string
+		Email string
+	}
+	type Account struct {
+		User   *User
+		Orders []Order
+		Meta   map[string]string
+		Status string
+	}
+
+	left := Account{
+		User:   &User{Name: "a", Email: "a@example.com"},
+		Orders: []Order{{ID: 1, Total: 10, Note: "x"}},
+		Meta:   map[string]string{"env": "prod", "team": "x"},
+		Status: "active",
+	}
+	right := Account{
+		User:   &User{Name: "b", Email: "b@example.com"},
+		Orders: []Order{{ID: 1, Total: 12, Note: "y"}, {ID: 2, Total: 5}},
+		Meta:   map[string]string{"env": "dev", "team": "y"},
+		Status: "closed",
+	}
+
+	tests := []struct {
+		name     string
+		paths    []string
+		expected []string
+	}{
+		{"nested field", []string{"User.Email"}, []string{"User.Email"}},
+		{"slice wildcard", []string{"Orders[*].Total"}, []string{"Orders[0].Total"}},
+		{"whole field", []string{"Orders", "Status"}, []string{"Orders", "Orders[0].Total", "Orders[0].Note", "Status"}},
+		{"map entry", []string{"Meta[env]"}, []string{"Meta[env]"}},
+		{"any depth", []string{"**.Email"}, []string{"User.Email"}},
+		{"not present", []string{"Missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(left, right, WithOnlyFields(tt.paths...))
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			var paths []string
+			for _, diff := range result.Diffs {
+				paths = append(paths, diff.base().Path)
+			}
+			slices.Sort(paths)
+			expected := slices.Sorted(slices.Values(tt.expected))
+			if !slices.Equal(paths, expected) {
+				t.Errorf("Expected %v, got %v", expected, paths)
+			}
+		})
+	}
+
+	result, err := Compare(left, right, WithOnlyFields("Orders"), WithIgnoreFields("Note"))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 2 {
+		t.Errorf("Expected 2 differences, got %d: %s", len(result.Diffs), result.String())
+	}
+
+	if _, err := Compare(left, right, WithOnlyFields("re:User")); err == nil {
+		t.Error("Expected error for regex allow pattern")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	type Profile struct {
+		Name     string
+		Password string `json:"-"`
+		Cache    string `gorm:"-"`
+		Scores   []int
+		Labels   map[string]string
+	}
+
+	left := Profile{Name: "a", Password: "x", Cache: "c1", Scores: []int{1, 2}, Labels: map[string]string{"env": "prod", "tmp": "1"}}
+	right := Profile{Name: "b", Password: "y", Cache: "c2", Scores: []int{1, 3, 4}, Labels: map[string]string{"env": "dev", "tmp": "2", "tmp2": "3"}}
+
+	var contexts []FilterContext
+	result, err := Compare(left, right,
+		WithFilter(func(ctx FilterContext) bool {
+			contexts = append(contexts, ctx)
+			return ctx.Field == nil || (ctx.Field.Tag.Get("json") != "-" && ctx.Field.Tag.Get("gorm") != "-")
+		}),
+		WithFilter(func(ctx FilterContext) bool {
+			key, _ := ctx.MapKey.(string)
+			return !strings.HasPrefix(key, "tmp") && ctx.Index != 2
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	var paths []string
+	for _, diff := range result.Diffs {
+		paths = append(paths, diff.Location().String())
+	}
+	slices.Sort(paths)
+	expected := []string{"Labels[env]", "Name", "Scores[1]"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	for _, ctx := range contexts {
+		switch ctx.Path.String() {
+		case "Name":
+			if ctx.Field == nil || ctx.Field.Name != "Name" || ctx.ParentType != reflect.TypeFor[Profile]() ||
+				ctx.Left != "a" || ctx.Right != "b" || ctx.Index != -1 {
+				t.Errorf("Unexpected field context %+v", ctx)
+			}
+		case "Scores[2]":
+			if ctx.Field != nil || ctx.Index != 2 || ctx.Left != nil || ctx.Right != 4 {
+				t.Errorf("Unexpected slice context %+v", ctx)
+			}
+		case "Labels[tmp2]":
+			if ctx.MapKey != "tmp2" || ctx.Left != nil || ctx.Right != "3" || ctx.ParentType != reflect.TypeFor[map[string]string]() {
+				t.Errorf("Unexpected map context %+v", ctx)
+			}
+		}
+	}
+}
+
+func TestFilterSliceModes(t *testing.T) {
+	type item struct {
+		ID    int
+		Name  string
+		Draft bool
+	}
+	left := []item{{1, "a", false}, {2, "b", true}, {3, "c", false}}
+	right := []item{{1, "a", false}, {2, "x", true}, {3, "z", false}, {4, "d", true}}
+
+	// skip draft elements on either side
+	skipDrafts := WithFilter(func(ctx FilterContext) bool {
+		for _, v := range []any{ctx.Left, ctx.Right} {
+			if it, ok := v.(item); ok && it.Draft {
+				return false
+			}
+		}
+		return true
+	})
+
+	tests := []struct {
+		name     string
+		opts     []CompareOption
+		expected []string
+	}{
+		{"positional", nil, []string{"updated [2].Name"}},
+		{"slice key", []CompareOption{WithSliceKey("", "ID")}, []string{"updated [ID=3].Name"}},
+		{"lcs", []CompareOption{WithSliceAlgorithm(SliceLCS)}, []string{"updated [2].Name"}},
+		{"detect moves", []CompareOption{WithDetectMoves()}, []string{"updated [2].Name"}},
+		{"ignore order", []CompareOption{WithIgnoreSliceOrder()}, []string{"removed ", "added "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(left, right, append(tt.opts, skipDrafts)...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			var got []string
+			for _, diff := range result.Diffs {
+				got = append(got, fmt.Sprintf("%s %s", diff.Kind(), diff.Location()))
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	var calls []string
+	recordSides := WithFilter(func(ctx FilterContext) bool {
+		calls = append(calls, fmt.Sprintf("%v/%v", ctx.Left, ctx.Right))
+		return true
+	})
+	if _, err := Compare([]int{1, 2}, []int{3}, WithIgnoreSliceOrder(), recordSides); err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if expected := []string{"1/<nil>", "2/<nil>", "<nil>/3"}; !slices.Equal(calls, expected) {
+		t.Errorf("Expected each element filtered once on its own side %v, got %v", expected, calls)
+	}
+}
+
+func TestDiffTagDirectives(t *testing.T) {
+	type Node struct {
+		Value int
+		Next  *Node
+	}
+	type Record struct {
+		ID       int               `diff:"name=id"`
+		Code     string            `diff:"ignoreCase"`
+		Text     string            `diff:"ignoreWhitespace"`
+		Tags     []string          `diff:"ignoreCase,ignoreOrder"`
+		Password string            `diff:"redact"`
+		Amount   string            `diff:"compare=numeric"`
+		Head     *Node             `diff:"shallow"`
+		Tree     *Node             `diff:"deep"`
+		Items    []int             `diff:"omitEmptyEqual"`
+		Labels   map[string]string `diff:"omitEmptyEqual"`
+	}
+
+	numeric := WithNamedComparator("numeric", func(left, right any, config *CompareConfig) (bool, error) {
+		var l, r float64
+		if _, err := fmt.Sscan(left.(string), &l); err != nil {
+			return false, err
+		}
+		if _, err := fmt.Sscan(right.(string), &r); err != nil {
+			return false, err
+		}
+		return l == r, nil
+	})
+
+	deepTree := func(leaf int) *Node {
+		return &Node{Value: 1, Next: &Node{Value: 2, Next: &Node{Value: leaf}}}
+	}
+
+	left := Record{
+		ID: 1, Code: "ABC", Text: "a b\tc", Tags: []string{"x", "Y"}, Password: "old", Amount: "1.50",
+		Head: &Node{Value: 1, Next: &Node{Value: 2}}, Tree: deepTree(3), Items: nil, Labels: map[string]string{},
+	}
+	right := Record{
+		ID: 2, Code: "abc", Text: "abc", Tags: []string{"y", "X"}, Password: "new", Amount: "1.5",
+		Head: &Node{Value: 1, Next: &Node{Value: 3}}, Tree: deepTree(4), Items: []int{}, Labels: nil,
+	}
+
+	result, err := Compare(left, right, numeric, WithMaxDepth(3))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	changes := make(map[string]Change)
+	for _, diff := range result.Diffs {
+		changes[diff.base().Path] = diff
+	}
+	if len(changes) != 4 {
+		t.Errorf("Expected 4 differences, got %d: %s", len(result.Diffs), result.String())
+	}
+	if d, ok := changes["id"].(*StructDiff); !ok || d.FieldName != "id" {
+		t.Errorf("Expected a difference named id, got %s", result.String())
+	}
+	if d, ok := changes["Password"]; !ok || d.LeftValue() != Redacted || d.RightValue() != Redacted {
+		t.Errorf("Expected a redacted Password difference, got %s", result.String())
+	}
+	if strings.Contains(result.String(), "old") || strings.Contains(result.ToJSON(), "new") {
+		t.Errorf("Expected redacted values in the output, got %s", result.String())
+	}
+	if d, ok := changes["Head"]; !ok || d.LeftValue() != left.Head {
+		t.Errorf("Expected a shallow difference for Head, got %s", result.String())
+	}
+	if _, ok := changes["Tree.Next.Next.Value"]; !ok {
+		t.Errorf("Expected deep fields to ignore the max depth, got %s", result.String())
+	}
+
+	target := left
+	if err := result.Apply(&target); err == nil {
+		t.Error("Expected error when applying a redacted change")
+	}
+
+	if _, err := Compare(left, right); err == nil || !strings.Contains(err.Error(), `"numeric"`) {
+		t.Errorf("Expected error for unregistered comparator, got %v", err)
+	}
+}
+
+func TestNilEqualsEmptyAndZeroEqualsMissing(t *testing.T) {
+	type Inner struct {
+		Value int
+	}
+	type Payload struct {
+		Tags     []string
+		Labels   map[string]string
+		Extra    any
+		Inner    *Inner
+		Count    *int
+		Settings map[string]any
+		Items    []any
+	}
+
+	left := Payload{
+		Extra:    nil,
+		Settings: map[string]any{"list": nil, "zero": 0},
+		Items:    []any{nil, 1},
+	}
+	right := Payload{
+		Tags:     []string{},
+		Labels:   map[string]string{},
+		Extra:    []int{},
+		Inner:    &Inner{},
+		Count:    new(int),
+		Settings: map[string]any{"list": []any{}, "missing": map[string]any{}, "blank": ""},
+		Items:    []any{[]string{}, 1},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []CompareOption
+		expected []string
+	}{
+		{"default", nil, []string{"Extra", "Inner", "Count", "Settings[list]", "Settings[zero]", "Settings[missing]", "Settings[blank]", "Items"}},
+		{"nil equals empty", []CompareOption{WithNilEqualsEmpty()}, []string{"Inner", "Count", "Settings[zero]", "Settings[blank]"}},
+		{"zero equals missing", []CompareOption{WithZeroEqualsMissing()}, []string{"Extra", "Settings[list]", "Settings[missing]", "Items"}},
+		{"both", []CompareOption{WithNilEqualsEmpty(), WithZeroEqualsMissing()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(left, right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			var paths []string
+			for _, diff := range result.Diffs {
+				paths = append(paths, diff.base().Path)
+			}
+			slices.Sort(paths)
+			expected := slices.Sorted(slices.Values(tt.expected))
+			if !slices.Equal(paths, expected) {
+				t.Errorf("Expected %v, got %v", expected, paths)
+			}
+		})
+	}
+}
+
+type privateState struct {
+	Name    string
+	balance int
+	history []string
+	meta    *privateMeta
+	created time.Time
+}
+
+type privateMeta struct {
+	version int
+}
+
+func TestUnexportedFields(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	left := privateState{Name: "a", balance: 10, history: []string{"open"}, meta: &privateMeta{version: 1}, created: created}
+	right := privateState{Name: "a", balance: 20, history: []string{"open", "deposit"}, meta: &privateMeta{version: 2}, created: created.Add(time.Hour)}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected unexported fields to be skipped by default, got %s", result.String())
+	}
+
+	paths := func(result *DiffResult) []string {
+		var paths []string
+		for _, diff := range result.Diffs {
+			paths = append(paths, diff.Location().String())
+		}
+		return paths
+	}
+
+	result, err = Compare(left, right, WithUnexportedFields())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	expected := []string{"balance", "history[1]", "meta.version", "created"}
+	if got := paths(result); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if d := result.Diffs[0]; d.LeftValue() != 10 || d.RightValue() != 20 {
+		t.Errorf("Expected balance 10 -> 20, got %v -> %v", d.LeftValue(), d.RightValue())
+	}
+
+	result, err = Compare(&left, &right, WithUnexportedFieldsOf(privateState{}))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	expected = []string{"balance", "history[1]", "created"}
+	if got := paths(result); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v with the per-type allow-list, got %v", expected, got)
+	}
+}
+
+type money struct {
+	cents    int64
+	currency string
+}
+
+func (m *money) Equal(other *money) bool {
+	return m.cents == other.cents && strings.EqualFold(m.currency, other.currency)
+}
+
+type version struct {
+	Major, Minor int
+	Label        string
+}
+
+func (v version) Equal(other version) bool {
+	return v.Major == other.Major && v.Minor == other.Minor
+}
+
+func TestEqualMethodHandler(t *testing.T) {
+	type Account struct {
+		Balance *big.Int
+		Addr    netip.Addr
+		Price   money
+		Version version
+	}
+
+	left := Account{
+		Balance: big.NewInt(100),
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Price:   money{cents: 500, currency: "usd"},
+		Version: version{Major: 1, Minor: 2, Label: "a"},
+	}
+	right := Account{
+		Balance: new(big.Int).SetInt64(100),
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Price:   money{cents: 500, currency: "USD"},
+		Version: version{Major: 1, Minor: 2, Label: "b"},
+	}
+
+	result, err := Compare(left, right, WithEqualMethods())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected no differences with equal methods, got %s", result.String())
+	}
+
+	result, err = Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 1 || result.Diffs[0].base().Path != "Version.Label" {
+		t.Errorf("Expected only Version.Label to differ without equal methods, got %s", result.String())
+	}
+
+	right.Balance = big.NewInt(101)
+	right.Addr = netip.MustParseAddr("10.0.0.2")
+	right.Version.Minor = 3
+	result, err = Compare(left, right, WithEqualMethods())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	var paths []string
+	for _, diff := range result.Diffs {
+		paths = append(paths, diff.base().Path)
+	}
+	if expected := []string{"Balance", "Addr", "Version"}; !slices.Equal(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	right.Balance = nil
+	result, err = Compare(left, right, WithEqualMethods())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Diffs[0].Kind() != ChangeTypeRemoved {
+		t.Errorf("Expected nil balance to be reported as removed, got %s", result.String())
+	}
+
+	handler := &EqualMethodHandler{}
+	if handler.CanHandle(reflect.TypeFor[TestStruct]()) {
+		t.Error("Expected TestStruct not to be handled")
+	}
+}
+
+func TestStdlibHandlers(t *testing.T) {
+	mustURL := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		return u
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		left, right any
+		diffs       int
+	}{
+		{"big.Int equal", big.NewInt(42), new(big.Int).SetBytes([]byte{42}), 0},
+		{"big.Int different", big.NewInt(42), big.NewInt(43), 1},
+		{"big.Int nil", (*big.Int)(nil), big.NewInt(0), 1},
+		{"big.Float equal", big.NewFloat(1.5), new(big.Float).SetPrec(200).SetFloat64(1.5), 0},
+		{"big.Rat equal", big.NewRat(1, 2), big.NewRat(2, 4), 0},
+		{"big.Rat different", big.NewRat(1, 2), big.NewRat(1, 3), 1},
+		{"netip.Addr equal", netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.1"), 0},
+		{"netip.Prefix different", netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("10.0.0.0/16"), 1},
+		{"net.IP v4 in v6", net.ParseIP("10.0.0.1"), net.IPv4(10, 0, 0, 1).To4(), 0},
+		{"net.IP different", net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2"), 1},
+		{"url equal", mustURL("https://example.com/a?b=c"), mustURL("https://example.com/a?b=c"), 0},
+		{"url different", mustURL("https://example.com/a"), mustURL("https://example.com/b"), 1},
+		{"regexp equal", regexp.MustCompile(`^a+$`), regexp.MustCompile(`^a+$`), 0},
+		{"regexp different", regexp.MustCompile(`^a+$`), regexp.MustCompile(`^b+$`), 1},
+		{"location equal", berlin, time.FixedZone("Europe/Berlin", 3600), 0},
+		{"location different", berlin, time.UTC, 1},
+		{"json equal", json.RawMessage(`{"a": 1, "b": [1, 2]}`), json.RawMessage(`{"b":[1,2],"a":1}`), 0},
+		{"json different", json.RawMessage(`{"a": 1}`), json.RawMessage(`{"a": 2}`), 1},
+		{"json invalid", json.RawMessage(`{`), json.RawMessage(`{ `), 1},
+		{"sql both null", sql.NullString{String: "a"}, sql.NullString{String: "b"}, 0},
+		{"sql equal", sql.NullInt64{Int64: 1, Valid: true}, sql.NullInt64{Int64: 1, Valid: true}, 0},
+		{"sql different", sql.NullInt64{Int64: 1, Valid: true}, sql.NullInt64{Int64: 2, Valid: true}, 1},
+		{"sql generic", sql.Null[float64]{V: 1, Valid: true}, sql.Null[float64]{V: 1, Valid: true}, 0},
+		{"bytes equal", []byte{1, 2, 3}, []byte{1, 2, 3}, 0},
+		{"bytes different", []byte{1, 2, 3}, []byte{1, 2, 4, 5}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if len(result.Diffs) != tt.diffs {
+				t.Errorf("Expected %d diffs, got %d: %s", tt.diffs, len(result.Diffs), result.String())
+			}
+		})
+	}
+
+	type record struct {
+		Name    sql.NullString
+		Payload []byte
+		Doc     json.RawMessage
+	}
+	left := record{Payload: []byte{0xca, 0xfe}, Doc: json.RawMessage(`{"a":1}`)}
+	right := record{Name: sql.NullString{String: "x", Valid: true}, Payload: []byte{0xbe, 0xef}, Doc: json.RawMessage(`{"a":2}`)}
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 3 || result.Diffs[0].Kind() != ChangeTypeAdded {
+		t.Fatalf("Expected 3 diffs starting with an addition, got %s", result.String())
+	}
+	output := result.String()
+	for _, expected := range []string{"Payload: 0xcafe -> 0xbeef", "Doc.a: 1 -> 2"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got %s", expected, output)
+		}
+	}
+	if output := result.ToJSON(); !strings.Contains(output, `"yv4="`) {
+		t.Errorf("Expected base64 bytes in JSON output, got %s", output)
+	}
+	target := left
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !reflect.DeepEqual(target, right) {
+		t.Errorf("Expected %+v after Apply, got %+v", right, target)
+	}
+}
+
+func TestJSONDocuments(t *testing.T) {
+	type order struct {
+		ID      int
+		Payload string `diff:"json"`
+		Raw     json.RawMessage
+	}
+
+	left := order{
+		ID:      1,
+		Payload: `{"customer": "ann", "items": [{"sku": "a", "price": 10}, {"sku": "b", "price": 20}, {"sku": "c", "price": 30}], "note": "x"}`,
+		Raw:     json.RawMessage(`[1, 2, 3]`),
+	}
+	right := order{
+		ID:      1,
+		Payload: `{"items":[{"price":10,"sku":"a"},{"price":20,"sku":"b"},{"price":35,"sku":"c"}],"customer":"ann","gift":true}`,
+		Raw:     json.RawMessage(`[1, 2]`),
+	}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	expected := map[string]ChangeType{
+		"Payload.items[2].price": ChangeTypeUpdated,
+		"Payload.gift":           ChangeTypeAdded,
+		"Payload.note":           ChangeTypeRemoved,
+		"Raw[2]":                 ChangeTypeRemoved,
+	}
+	if len(result.Diffs) != len(expected) {
+		t.Fatalf("Expected %d diffs, got %s", len(expected), result.String())
+	}
+	for _, diff := range result.Diffs {
+		location := diff.Location().String()
+		if ct, ok := expected[location]; !ok || ct != diff.Kind() {
+			t.Errorf("Unexpected %s diff at %s", diff.Kind(), location)
+		}
+	}
+
+	patch, err := result.ToJSONPatch()
+	if err != nil {
+		t.Fatalf("ToJSONPatch failed: %v", err)
+	}
+	// JSON documents are replaced as a whole, once
+	if len(patch) != 2 || !slices.ContainsFunc(patch, func(op JSONPatchOperation) bool {
+		return op.Op == JSONPatchReplace && op.Path == "/Payload" && op.Value == right.Payload
+	}) {
+		t.Errorf("Expected /Payload and /Raw to be replaced, got %+v", patch)
+	}
+
+	target := left
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if check, err := Compare(target, right); err != nil || check.HasDifferences() {
+		t.Errorf("Expected no differences after Apply, got %v %v", check, err)
+	}
+	if err := result.Revert(&target); err != nil {
+		t.Fatalf("Revert failed: %v", err)
+	}
+	if check, err := Compare(target, left); err != nil || check.HasDifferences() {
+		t.Errorf("Expected no differences after Revert, got %v %v", check, err)
+	}
+
+	result, err = Compare(left, right, WithIgnoreFields("Payload.items[*].price", "Payload.note", "Payload.gift", "Raw"))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.HasDifferences() {
+		t.Errorf("Expected ignored JSON members to produce no differences, got %s", result.String())
+	}
+
+	dotted := order{Payload: `{"a.b": {"c": 1}, "x[0]": 1}`}
+	redotted := order{Payload: `{"a.b": {"c": 2}, "x[0]": 2}`}
+	result, err = Compare(dotted, redotted)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 2 || result.Diffs[0].Location().String() != "Payload[a.b].c" || result.Diffs[1].Location().String() != `Payload["x[0]"]` {
+		t.Fatalf("Expected map key steps for member names that are not fields, got %s", result.String())
+	}
+	for _, diff := range result.Diffs {
+		if parsed, err := ParsePath(diff.Location().String()); err != nil || parsed.String() != diff.Location().String() || len(parsed) != len(diff.Location()) {
+			t.Errorf("Expected %s to parse back into %d steps, got %v, %v", diff.Location(), len(diff.Location()), parsed, err)
+		}
+	}
+	target = dotted
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if check, err := Compare(target, redotted); err != nil || check.HasDifferences() {
+		t.Errorf("Expected no differences after Apply, got %v %v", check, err)
+	}
+
+	invalid := order{Payload: `{"broken"`}
+	result, err = Compare(invalid, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 3 || result.Diffs[0].Location().String() != "ID" || result.Diffs[1].Location().String() != "Payload" {
+		t.Errorf("Expected invalid JSON to be compared as a whole, got %s", result.String())
+	}
+
+	type badTag struct {
+		Count int `diff:"json"`
+	}
+	if _, err := Compare(badTag{}, badTag{Count: 1}); err == nil || !strings.Contains(err.Error(), `directive "json"`) {
+		t.Errorf("Expected an error for a json tag on an int field, got %v", err)
+	}
+}
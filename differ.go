@@ -0,0 +1,102 @@
+package godiff
+
+import "reflect"
+
+// CompareOf compares two values of the same type T and returns the differences. Unlike
+// Compare, mismatched argument types are rejected by the compiler.
+func CompareOf[T any](left, right T, opts ...CompareOption) (*DiffResult, error) {
+	return Compare(left, right, opts...)
+}
+
+// Differ compares values of type T with a fixed set of options. NewDiffer applies the
+// options once and resolves a plan for T and every type reachable from it: the parsed diff
+// tags of struct types and the custom comparator or type handler comparing each type.
+// Values whose dynamic types are not part of the plan, such as those held in interfaces,
+// are resolved as Compare does. A Differ is safe for concurrent use.
+type Differ[T any] struct {
+	config *CompareConfig
+}
+
+// NewDiffer returns a Differ for values of type T. It fails if an option is invalid or a
+// struct type reachable from T has an invalid diff tag.
+func NewDiffer[T any](opts ...CompareOption) (*Differ[T], error) {
+	config, err := newCompareConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	plans := make(map[reflect.Type]*typePlan)
+	if err := config.planType(reflect.TypeFor[T](), plans); err != nil {
+		return nil, err
+	}
+	config.plans = plans
+	return &Differ[T]{config: config}, nil
+}
+
+// Compare compares two values and returns the differences
+func (d *Differ[T]) Compare(left, right T) (*DiffResult, error) {
+	return runCompare(left, right, d.comparisonConfig(), d.config.MaxDiffs)
+}
+
+// Equal reports whether two values are equal, stopping at the first difference. It panics
+// if a comparator or type handler fails; EqualE returns the error instead.
+func (d *Differ[T]) Equal(left, right T) bool {
+	equal, err := d.EqualE(left, right)
+	if err != nil {
+		panic(err)
+	}
+	return equal
+}
+
+// EqualE is Equal returning the errors of comparators and type handlers
+func (d *Differ[T]) EqualE(left, right T) (bool, error) {
+	return equalWith(left, right, d.comparisonConfig())
+}
+
+// comparisonConfig returns a copy of the prepared configuration with its own comparison state
+func (d *Differ[T]) comparisonConfig() *CompareConfig {
+	config := *d.config
+	config.visitedPairs = make(map[[2]uintptr]bool)
+	return &config
+}
+
+// typePlan is what a Differ resolves once for a type instead of on every comparison
+type typePlan struct {
+	// comparator is the custom comparator registered for the type
+	comparator func(left, right any, config *CompareConfig) (bool, error)
+	// handler is the type handler comparing the type, if there is no comparator
+	handler TypeHandler
+	// tags are the parsed diff tags of a struct type
+	tags []fieldTag
+}
+
+// planType resolves the plans of typ and of the types it contains, reporting the first
+// invalid diff tag
+func (c *CompareConfig) planType(typ reflect.Type, plans map[reflect.Type]*typePlan) error {
+	if _, seen := plans[typ]; seen {
+		return nil
+	}
+	plan := &typePlan{}
+	plans[typ] = plan
+	plan.comparator, plan.handler = c.resolveType(typ)
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return c.planType(typ.Elem(), plans)
+	case reflect.Map:
+		if err := c.planType(typ.Key(), plans); err != nil {
+			return err
+		}
+		return c.planType(typ.Elem(), plans)
+	case reflect.Struct:
+		tags, err := tagsFor(typ)
+		if err != nil {
+			return err
+		}
+		plan.tags = tags
+		for i := range typ.NumField() {
+			if err := c.planType(typ.Field(i).Type, plans); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package godiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// decodeJSONPair decodes two JSON documents, reporting false if either is invalid
+func decodeJSONPair(left, right []byte) (leftDoc, rightDoc any, ok bool) {
+	if json.Unmarshal(left, &leftDoc) != nil || json.Unmarshal(right, &rightDoc) != nil {
+		return nil, nil, false
+	}
+	return leftDoc, rightDoc, true
+}
+
+// jsonDocument returns the JSON text held by a string or byte slice value
+func jsonDocument(v reflect.Value) []byte {
+	if v.Kind() == reflect.String {
+		return []byte(v.String())
+	}
+	return v.Bytes()
+}
+
+// compareJSONField compares a field tagged `diff:"json"` by decoding both documents and
+// diffing the decoded trees. Fields that are not valid JSON are compared as a whole.
+func compareJSONField(fieldPath Path, leftVal, rightVal reflect.Value, result *DiffResult, config *CompareConfig) error {
+	leftDoc, rightDoc := jsonDocument(leftVal), jsonDocument(rightVal)
+	if bytes.Equal(leftDoc, rightDoc) {
+		return nil
+	}
+	left, right := leftVal.Interface(), rightVal.Interface()
+	compared, err := compareJSONDocuments(fieldPath, leftDoc, rightDoc, result, config)
+	if err != nil {
+		return err
+	}
+	if !compared {
+		result.addStructDiff(fieldPath, left, right, changeTypeFor(left, right))
+	}
+	return nil
+}
+
+// compareJSONDocuments records the differences between the members and elements of two
+// JSON objects or arrays. It reports false, recording nothing, if the documents are
+// invalid, scalars or of different types and therefore have to be compared as a whole.
+func compareJSONDocuments(path Path, left, right []byte, result *DiffResult, config *CompareConfig) (bool, error) {
+	leftDoc, rightDoc, ok := decodeJSONPair(left, right)
+	if !ok {
+		return false, nil
+	}
+	switch l := leftDoc.(type) {
+	case map[string]any:
+		if r, ok := rightDoc.(map[string]any); ok {
+			return true, compareJSONObjects(path, l, r, result, config)
+		}
+	case []any:
+		if r, ok := rightDoc.([]any); ok {
+			return true, compareJSONArrays(path, l, r, result, config)
+		}
+	}
+	return reflect.DeepEqual(leftDoc, rightDoc), nil
+}
+
+// compareJSONValues compares two decoded JSON values. Object members are addressed by
+// jsonMember, so that paths read like `Payload.items[2].price`.
+func compareJSONValues(path Path, left, right any, result *DiffResult, config *CompareConfig) error {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			return compareJSONObjects(path, l, r, result, config)
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return compareJSONArrays(path, l, r, result, config)
+		}
+	}
+	if reflect.DeepEqual(left, right) {
+		return nil
+	}
+	sameType := left != nil && right != nil && reflect.TypeOf(left) == reflect.TypeOf(right)
+	if sameType && config.relaxesBasicValues() && basicValuesEqual(left, right, config) {
+		return nil
+	}
+	changeType := changeTypeFor(left, right)
+	if left != nil && right != nil && !sameType {
+		changeType = ChangeTypeTypeChanged
+	}
+	result.addDiff(path, left, right, changeType)
+	return nil
+}
+
+// jsonMember returns path extended by the object member key. Members are addressed by
+// field steps, unless their names would not read back as a single field and need a
+// map key step, e.g. `Payload[a.b]`.
+func jsonMember(path Path, key string) Path {
+	if key == "" || strings.ContainsAny(key, `.[]"`) {
+		return path.key(key)
+	}
+	return path.field(key)
+}
+
+// compareJSONObjects compares the members of two JSON objects in key order
+func compareJSONObjects(path Path, left, right map[string]any, result *DiffResult, config *CompareConfig) error {
+	keys := slices.AppendSeq(make([]string, 0, len(left)+len(right)), maps.Keys(left))
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		memberPath := jsonMember(path, key)
+		if result.limitReached() {
+			return result.truncate(memberPath)
+		}
+		if config.isPathIgnored(memberPath) {
+			continue
+		}
+		leftMember, inLeft := left[key]
+		rightMember, inRight := right[key]
+		switch {
+		case !inLeft:
+			result.addMapDiff(memberPath, key, nil, rightMember, ChangeTypeAdded)
+		case !inRight:
+			result.addMapDiff(memberPath, key, leftMember, nil, ChangeTypeRemoved)
+		default:
+			if err := compareJSONValues(memberPath, leftMember, rightMember, result, config); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compareJSONArrays compares the elements of two JSON arrays by position
+func compareJSONArrays(path Path, left, right []any, result *DiffResult, config *CompareConfig) error {
+	for i := range max(len(left), len(right)) {
+		if result.limitReached() {
+			return result.truncate(path.index(i))
+		}
+		if config.isPathIgnored(path.index(i)) {
+			continue
+		}
+		switch {
+		case i >= len(left):
+			result.addSliceDiff(path, i, nil, right[i], ChangeTypeAdded)
+		case i >= len(right):
+			result.addSliceDiff(path, i, left[i], nil, ChangeTypeRemoved)
+		default:
+			if err := compareJSONValues(path.index(i), left[i], right[i], result, config); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,483 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSON Patch operation names as defined by RFC 6902
+const (
+	JSONPatchAdd     = "add"
+	JSONPatchRemove  = "remove"
+	JSONPatchReplace = "replace"
+	JSONPatchMove    = "move"
+	JSONPatchTest    = "test"
+)
+
+// JSONPatchOperation is a single RFC 6902 JSON Patch operation
+type JSONPatchOperation struct {
+	Op    string // One of add, remove, replace, move or test
+	Path  string // RFC 6901 JSON Pointer to the target location
+	From  string // Source location for move operations
+	Value any    // Value for add, replace and test operations
+}
+
+// MarshalJSON encodes the operation with only the members RFC 6902 defines for its op,
+// so that a nil, zero or false value is still written for add, replace and test
+func (op JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	switch op.Op {
+	case JSONPatchRemove:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	case JSONPatchMove:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{op.Op, op.From, op.Path})
+	default:
+		return json.Marshal(struct {
+			Op    string `json:"op"`
+			Path  string `json:"path"`
+			Value any    `json:"value"`
+		}{op.Op, op.Path, op.Value})
+	}
+}
+
+// JSONPatch is an RFC 6902 JSON Patch document
+type JSONPatch []JSONPatchOperation
+
+// JSONPatchOption is a function that modifies how a JSON Patch is generated
+type JSONPatchOption func(*jsonPatchConfig)
+
+type jsonPatchConfig struct {
+	testOps bool
+}
+
+// WithTestOperations precedes every remove and replace operation with a test
+// operation asserting the old value
+func WithTestOperations() JSONPatchOption {
+	return func(c *jsonPatchConfig) {
+		c.testOps = true
+	}
+}
+
+// ToJSONPatch converts the diff result into an RFC 6902 JSON Patch that turns the JSON
+// encoding of the left value into the JSON encoding of the right value. Paths are
+// RFC 6901 JSON Pointers built from json tag names, map keys and slice indexes; fields
+// tagged `json:"-"` are left out.
+func (dr *DiffResult) ToJSONPatch(opts ...JSONPatchOption) (JSONPatch, error) {
+	config := &jsonPatchConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	patch := JSONPatch{}
+	// changes inside JSON documents replace the document as a whole
+	var documents []Path
+	seen := make(map[string]bool)
+	diffs := slices.DeleteFunc(slices.Clone(dr.Diffs), func(diff Change) bool {
+		document, ok := dr.jsonDocumentSteps(valueSteps(diff.base()))
+		if ok && !seen[document.String()] {
+			seen[document.String()] = true
+			documents = append(documents, document)
+		}
+		return ok
+	})
+	groups, others := groupSliceChanges(diffs)
+	groupAt := make(map[string]*sliceChangeGroup, len(groups))
+	for _, g := range groups {
+		groupAt[g.path] = g
+	}
+	jsonPointer := func(path Path) (string, bool) { return dr.patchedJSONPointer(path, groupAt) }
+
+	for _, g := range groups {
+		pointer, omitted := jsonPointer(g.steps)
+		if omitted {
+			continue
+		}
+
+		var removals, insertions, moves []*SliceDiff
+		for _, d := range g.diffs {
+			switch d.ChangeType {
+			case ChangeTypeRemoved:
+				removals = append(removals, d)
+			case ChangeTypeMoved:
+				moves = append(moves, d)
+			default:
+				insertions = append(insertions, d)
+			}
+		}
+		slices.SortFunc(removals, func(a, b *SliceDiff) int { return b.Index - a.Index })
+		slices.SortFunc(insertions, func(a, b *SliceDiff) int { return a.Index - b.Index })
+
+		for _, d := range removals {
+			patch.append(config, JSONPatchRemove, pointer+"/"+strconv.Itoa(d.Index), d.Left, nil)
+		}
+		if len(moves) == 0 {
+			for _, d := range insertions {
+				patch.append(config, JSONPatchAdd, pointer+"/"+strconv.Itoa(d.Index), nil, d.Right)
+			}
+			continue
+		}
+
+		container := indirectValue(lookupPath(reflect.ValueOf(dr.left), g.steps))
+		if container.Kind() != reflect.Slice && container.Kind() != reflect.Array {
+			return nil, fmt.Errorf("cannot resolve left-side slice at %q to place moved elements", g.path)
+		}
+		patch.appendArrangement(config, pointer, container.Len(), removals, insertions, moves)
+	}
+
+	for _, document := range documents {
+		pointer, omitted := jsonPointer(document)
+		if omitted {
+			continue
+		}
+		left := lookupPath(reflect.ValueOf(dr.left), document)
+		right := lookupPath(reflect.ValueOf(dr.right), document)
+		if !left.IsValid() || !right.IsValid() {
+			return nil, fmt.Errorf("cannot resolve JSON document at %q", document)
+		}
+		patch.appendValue(config, pointer, left.Interface(), right.Interface())
+	}
+
+	// original indexes already removed from unordered slices, per slice pointer
+	removedIndexes := make(map[string][]int)
+
+	for _, diff := range others {
+		switch d := diff.(type) {
+		case *MapDiff:
+			pointer, omitted := jsonPointer(valueSteps(&d.Diff))
+			if omitted {
+				continue
+			}
+			switch d.ChangeType {
+			case ChangeTypeAdded:
+				patch.append(config, JSONPatchAdd, pointer, nil, d.Right)
+			case ChangeTypeRemoved:
+				patch.append(config, JSONPatchRemove, pointer, d.Left, nil)
+			default:
+				patch.append(config, JSONPatchReplace, pointer, d.Left, d.Right)
+			}
+		case *SliceDiff:
+			pointer, omitted := jsonPointer(containerSteps(d))
+			if !omitted {
+				patch.append(config, JSONPatchReplace, pointer+"/"+strconv.Itoa(d.Index), d.Left, d.Right)
+			}
+		case *StructDiff:
+			pointer, omitted := jsonPointer(valueSteps(&d.Diff))
+			if !omitted {
+				patch.appendValue(config, pointer, d.Left, d.Right)
+			}
+		case *Diff:
+			steps := valueSteps(d)
+			pointer, omitted := jsonPointer(steps)
+			if omitted {
+				continue
+			}
+
+			container := lookupPath(reflect.ValueOf(dr.left), steps)
+			if !isSliceElementChange(container, d.Left, d.Right) {
+				patch.appendValue(config, pointer, d.Left, d.Right)
+				continue
+			}
+
+			if d.Right != nil {
+				patch.append(config, JSONPatchAdd, pointer+"/-", nil, d.Right)
+				continue
+			}
+			index, ok := unorderedRemovalIndex(indirectValue(container), d.Left, removedIndexes[pointer])
+			if !ok {
+				return nil, fmt.Errorf("cannot locate removed element %v at %q", d.Left, d.Path)
+			}
+			shift := 0
+			for _, removed := range removedIndexes[pointer] {
+				if removed < index {
+					shift++
+				}
+			}
+			removedIndexes[pointer] = append(removedIndexes[pointer], index)
+			patch.append(config, JSONPatchRemove, pointer+"/"+strconv.Itoa(index-shift), d.Left, nil)
+		}
+	}
+
+	return patch, nil
+}
+
+// appendArrangement adds the add and move operations that turn a slice of length n, from which
+// removals were already removed, into its right-side arrangement. Target positions are filled
+// in ascending order: inserted elements are added, all others are moved there when needed.
+func (p *JSONPatch) appendArrangement(config *jsonPatchConfig, pointer string, n int, removals, insertions, moves []*SliceDiff) {
+	const added = -1
+	removed := make(map[int]bool, len(removals))
+	for _, d := range removals {
+		removed[d.Index] = true
+	}
+
+	// target holds the left index of the element at each right index, or added
+	target := make([]int, n-len(removals)+len(insertions))
+	filled := make([]bool, len(target))
+	movedFrom := make(map[int]bool, len(moves))
+	for _, d := range moves {
+		target[d.ToIndex], filled[d.ToIndex] = d.FromIndex, true
+		movedFrom[d.FromIndex] = true
+	}
+	for _, d := range insertions {
+		target[d.Index], filled[d.Index] = added, true
+	}
+	next := 0
+	for j := range target {
+		if filled[j] {
+			continue
+		}
+		for removed[next] || movedFrom[next] {
+			next++
+		}
+		target[j] = next
+		next++
+	}
+
+	var state []int
+	for i := range n {
+		if !removed[i] {
+			state = append(state, i)
+		}
+	}
+
+	insertionAt := make(map[int]*SliceDiff, len(insertions))
+	for _, d := range insertions {
+		insertionAt[d.Index] = d
+	}
+	for j, want := range target {
+		if want == added {
+			p.append(config, JSONPatchAdd, pointer+"/"+strconv.Itoa(j), nil, insertionAt[j].Right)
+			state = slices.Insert(state, j, added)
+			continue
+		}
+		at := slices.Index(state[j:], want) + j
+		if at != j {
+			*p = append(*p, JSONPatchOperation{Op: JSONPatchMove, From: pointer + "/" + strconv.Itoa(at), Path: pointer + "/" + strconv.Itoa(j)})
+			state = slices.Insert(slices.Delete(state, at, at+1), j, want)
+		}
+	}
+}
+
+// append adds an operation, preceded by a test of the old value if configured
+func (p *JSONPatch) append(config *jsonPatchConfig, op, pointer string, oldValue, newValue any) {
+	if config.testOps && (op == JSONPatchRemove || op == JSONPatchReplace) {
+		*p = append(*p, JSONPatchOperation{Op: JSONPatchTest, Path: pointer, Value: oldValue})
+	}
+	*p = append(*p, JSONPatchOperation{Op: op, Path: pointer, Value: newValue})
+}
+
+// appendValue adds the operation that sets an object member or the whole document:
+// add when the old value was absent, replace otherwise
+func (p *JSONPatch) appendValue(config *jsonPatchConfig, pointer string, oldValue, newValue any) {
+	if oldValue == nil && pointer != "" {
+		p.append(config, JSONPatchAdd, pointer, nil, newValue)
+		return
+	}
+	p.append(config, JSONPatchReplace, pointer, oldValue, newValue)
+}
+
+// unorderedRemovalIndex returns the index in s of the first element equal to elem
+// that has not already been removed
+func unorderedRemovalIndex(s reflect.Value, elem any, removed []int) (int, bool) {
+	if s.Kind() != reflect.Slice {
+		return 0, false
+	}
+	for i := range s.Len() {
+		if !slices.Contains(removed, i) && reflect.DeepEqual(s.Index(i).Interface(), elem) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// patchedJSONPointer converts a diff path into an RFC 6901 JSON Pointer into the document
+// as patched by the slice change groups. Struct fields are named after their json tags,
+// resolved against the compared values; omitted is true if the path crosses a field tagged
+// `json:"-"`. Elements matched by identity are addressed at their index after the removals
+// and additions of their slice: unless moves restore the right-side order, the slice keeps
+// the left-side order of its elements.
+func (dr *DiffResult) patchedJSONPointer(path Path, groups map[string]*sliceChangeGroup) (pointer string, omitted bool) {
+	tokens, omitted := dr.jsonTokens(path)
+	if omitted {
+		return "", true
+	}
+	for i, token := range tokens {
+		if step := path[token.start]; step.Kind == StepIdentity {
+			container := path[:token.start]
+			tokens[i].name = strconv.Itoa(dr.patchedIndex(container, step, groups[container.String()]))
+		}
+	}
+	return joinJSONPointer(tokens), false
+}
+
+// patchedIndex returns the index of the element matched by an identity step in the slice
+// at path once the insertions and removals of g have been applied. Elements of slices with
+// moves, and elements without a key field, are addressed at their right-side index.
+func (dr *DiffResult) patchedIndex(path Path, step PathStep, g *sliceChangeGroup) int {
+	container := indirectValue(lookupPath(reflect.ValueOf(dr.left), path))
+	if container.Kind() != reflect.Slice && container.Kind() != reflect.Array {
+		return step.Index
+	}
+	index, hasField := identityIndex(container, step)
+	if !hasField || index < 0 {
+		return step.Index
+	}
+	if g == nil {
+		return index
+	}
+
+	var insertions []int
+	removed := 0
+	for _, d := range g.diffs {
+		switch d.ChangeType {
+		case ChangeTypeMoved:
+			return step.Index
+		case ChangeTypeRemoved:
+			if d.Index < index {
+				removed++
+			}
+		default:
+			insertions = append(insertions, d.Index)
+		}
+	}
+	index -= removed
+	slices.Sort(insertions)
+	for _, at := range insertions {
+		if at <= index {
+			index++
+		}
+	}
+	return index
+}
+
+// joinJSONPointer builds a JSON Pointer from its reference tokens
+func joinJSONPointer(tokens []jsonPathToken) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(escapeJSONPointerToken(token.name))
+	}
+	return sb.String()
+}
+
+// jsonPathToken is one member name or array index of a diff path in its JSON form
+type jsonPathToken struct {
+	name  string
+	array bool // token indexes an array rather than naming an object member
+	start int  // index of the path step the token was derived from
+}
+
+// jsonTokens converts a diff path into its JSON reference tokens, resolving struct
+// fields against the compared values. Untagged embedded structs produce no token
+// because encoding/json promotes their fields into the parent object.
+func (dr *DiffResult) jsonTokens(path Path) (tokens []jsonPathToken, omitted bool) {
+	left := reflect.ValueOf(dr.left)
+	right := reflect.ValueOf(dr.right)
+
+	for i, step := range path {
+		left, right = indirectValue(left), indirectValue(right)
+
+		token := jsonPathToken{start: i}
+		switch step.Kind {
+		case StepField:
+			token.name = step.Name
+			structVal := left
+			if structVal.Kind() != reflect.Struct {
+				structVal = right
+			}
+			if structVal.Kind() == reflect.Struct {
+				if field, ok := structVal.Type().FieldByName(step.Name); ok {
+					name, omit := jsonFieldName(field)
+					if omit {
+						return nil, true
+					}
+					token.name = name
+				}
+			}
+		case StepIndex:
+			token.name = strconv.Itoa(step.Index)
+			token.array = left.Kind() == reflect.Slice || left.Kind() == reflect.Array ||
+				right.Kind() == reflect.Slice || right.Kind() == reflect.Array
+		case StepIdentity:
+			// elements matched by identity are addressed at their right-side index,
+			// which patchedJSONPointer corrects
+			token.name = strconv.Itoa(step.Index)
+			token.array = true
+		case StepMapKey:
+			token.name = fmt.Sprintf("%v", step.Key)
+		default:
+			continue
+		}
+
+		left = lookupPath(left, path[i:i+1])
+		right = lookupPath(right, path[i:i+1])
+
+		if token.name != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens, false
+}
+
+// jsonDocumentSteps returns the leading steps of path that address a JSON document held in a
+// string or json.RawMessage, if path continues into the document. Exporters treat such
+// documents as single values: their JSON encoding is a string, or the document itself.
+func (dr *DiffResult) jsonDocumentSteps(path Path) (Path, bool) {
+	left := reflect.ValueOf(dr.left)
+	right := reflect.ValueOf(dr.right)
+	for i, step := range path {
+		if step.Kind == StepPointer || step.Kind == StepInterface {
+			continue
+		}
+		left, right = indirectValue(left), indirectValue(right)
+		if left.IsValid() && isJSONDocument(left) || right.IsValid() && isJSONDocument(right) {
+			return path[:i], true
+		}
+		left = lookupPath(left, path[i:i+1])
+		right = lookupPath(right, path[i:i+1])
+	}
+	return nil, false
+}
+
+// jsonFieldName returns the member name encoding/json uses for field. omit is true for
+// fields tagged `json:"-"`; an empty name marks an untagged embedded struct whose
+// fields are promoted into the parent object.
+func jsonFieldName(field reflect.StructField) (name string, omit bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	if name != "" {
+		return name, false
+	}
+	if field.Anonymous {
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		if typ.Kind() == reflect.Struct {
+			return "", false
+		}
+	}
+	return field.Name, false
+}
+
+// escapeJSONPointerToken escapes "~" and "/" as required by RFC 6901
+func escapeJSONPointerToken(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
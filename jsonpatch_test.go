@@ -0,0 +1,372 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type PatchBase struct {
+	ID int `json:"id"`
+}
+
+type patchItem struct {
+	PatchBase
+	Name     string            `json:"name"`
+	Price    float64           `json:"price,omitempty"`
+	Internal string            `json:"-"`
+	Tags     []string          `json:"tags"`
+	Attrs    map[string]string `json:"attrs"`
+	Owner    *Address          `json:"owner"`
+	Plain    bool
+}
+
+func TestToJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		opts     []CompareOption
+		patch    []JSONPatchOption
+		expected string
+	}{
+		{
+			name:     "struct fields use json tags",
+			left:     patchItem{PatchBase: PatchBase{ID: 1}, Name: "a", Internal: "x", Plain: true},
+			right:    patchItem{PatchBase: PatchBase{ID: 2}, Name: "b", Internal: "y", Plain: false},
+			expected: `[{"op":"replace","path":"/id","value":2},{"op":"replace","path":"/name","value":"b"},{"op":"replace","path":"/Plain","value":false}]`,
+		},
+		{
+			name:     "slice insertions and removals",
+			left:     patchItem{Tags: []string{"a", "b", "c", "d"}},
+			right:    patchItem{Tags: []string{"x", "b"}},
+			expected: `[{"op":"remove","path":"/tags/3"},{"op":"remove","path":"/tags/2"},{"op":"replace","path":"/tags/0","value":"x"}]`,
+		},
+		{
+			name:     "slice growth",
+			left:     []int{1},
+			right:    []int{1, 2, 3},
+			expected: `[{"op":"add","path":"/1","value":2},{"op":"add","path":"/2","value":3}]`,
+		},
+		{
+			name:     "slice matched by identity",
+			left:     []patchItem{{PatchBase: PatchBase{ID: 1}}, {PatchBase: PatchBase{ID: 2}}, {PatchBase: PatchBase{ID: 3}}},
+			right:    []patchItem{{PatchBase: PatchBase{ID: 1}}, {PatchBase: PatchBase{ID: 3}, Name: "c"}, {PatchBase: PatchBase{ID: 4}}},
+			opts:     []CompareOption{WithSliceKey("", "ID")},
+			expected: `[{"op":"remove","path":"/1"},{"op":"add","path":"/2","value":{"id":4,"name":"","tags":null,"attrs":null,"owner":null,"Plain":false}},{"op":"replace","path":"/1/name","value":"c"}]`,
+		},
+		{
+			name:     "moved elements",
+			left:     []string{"a", "b", "c", "d"},
+			right:    []string{"d", "a", "x", "c"},
+			opts:     []CompareOption{WithDetectMoves()},
+			expected: `[{"op":"move","from":"/3","path":"/0"},{"op":"replace","path":"/2","value":"x"}]`,
+		},
+		{
+			name:     "moved elements with removals and additions",
+			left:     []string{"a", "b", "c", "d"},
+			right:    []string{"d", "a", "c", "x", "y"},
+			opts:     []CompareOption{WithDetectMoves()},
+			expected: `[{"op":"remove","path":"/1"},{"op":"move","from":"/2","path":"/0"},{"op":"add","path":"/3","value":"x"},{"op":"add","path":"/4","value":"y"}]`,
+		},
+		{
+			name:     "map entries with escaped keys",
+			left:     patchItem{Attrs: map[string]string{"a/b": "1"}},
+			right:    patchItem{Attrs: map[string]string{"a/b": "3"}},
+			expected: `[{"op":"replace","path":"/attrs/a~1b","value":"3"}]`,
+		},
+		{
+			name:     "map entry removed",
+			left:     map[string]int{"gone": 1},
+			right:    map[string]int{},
+			expected: `[{"op":"remove","path":"/gone"}]`,
+		},
+		{
+			name:     "map entry added",
+			left:     patchItem{},
+			right:    patchItem{Attrs: map[string]string{"new~": "4"}},
+			expected: `[{"op":"add","path":"/attrs/new~0","value":"4"}]`,
+		},
+		{
+			name:     "nil pointer becomes value",
+			left:     patchItem{},
+			right:    patchItem{Owner: &Address{City: "Berlin"}},
+			expected: `[{"op":"add","path":"/owner","value":{"Street":"","City":"Berlin","Country":""}}]`,
+		},
+		{
+			name:     "nested pointer field",
+			left:     patchItem{Owner: &Address{City: "Berlin"}},
+			right:    patchItem{Owner: &Address{City: "Paris"}},
+			patch:    []JSONPatchOption{WithTestOperations()},
+			expected: `[{"op":"test","path":"/owner/City","value":"Berlin"},{"op":"replace","path":"/owner/City","value":"Paris"}]`,
+		},
+		{
+			name:     "unordered slice",
+			left:     []string{"a", "b", "c"},
+			right:    []string{"c", "d"},
+			opts:     []CompareOption{WithIgnoreSliceOrder()},
+			expected: `[{"op":"remove","path":"/0"},{"op":"remove","path":"/0"},{"op":"add","path":"/-","value":"d"}]`,
+		},
+		{
+			name:     "root value",
+			left:     "a",
+			right:    "b",
+			expected: `[{"op":"replace","path":"","value":"b"}]`,
+		},
+		{
+			name:     "no differences",
+			left:     1,
+			right:    1,
+			expected: `[]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			patch, err := result.ToJSONPatch(tt.patch...)
+			if err != nil {
+				t.Fatalf("ToJSONPatch failed: %v", err)
+			}
+			data, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestJSONPatchOperationMarshal(t *testing.T) {
+	data, err := json.Marshal(JSONPatchOperation{Op: JSONPatchMove, From: "/a", Path: "/b"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"op":"move","from":"/a","path":"/b"}` {
+		t.Errorf("Unexpected move encoding: %s", data)
+	}
+}
+
+func TestJSONPatchRoundTrip(t *testing.T) {
+	type order struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+	type customer struct {
+		Orders []order `json:"orders" diff:"key=ID"`
+	}
+	type document struct {
+		Payload json.RawMessage `json:"payload"`
+		Doc     string          `json:"doc" diff:"json"`
+	}
+
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		opts     []CompareOption
+		expected any // patched document if it differs from right
+	}{
+		{
+			name:  "keyed elements after removal",
+			left:  customer{Orders: []order{{1, "a"}, {2, "b"}, {3, "c"}}},
+			right: customer{Orders: []order{{3, "x"}, {1, "a"}}},
+			// a keyed comparison ignores order, so the left order is kept
+			expected: customer{Orders: []order{{1, "a"}, {3, "x"}}},
+		},
+		{
+			name:     "keyed elements after insertion",
+			left:     customer{Orders: []order{{1, "a"}, {2, "b"}}},
+			right:    customer{Orders: []order{{4, "d"}, {2, "x"}, {1, "y"}}},
+			expected: customer{Orders: []order{{4, "d"}, {1, "y"}, {2, "x"}}},
+		},
+		{
+			name:  "keyed elements with moves",
+			left:  customer{Orders: []order{{1, "a"}, {2, "b"}, {3, "c"}}},
+			right: customer{Orders: []order{{3, "x"}, {4, "d"}, {1, "y"}}},
+			opts:  []CompareOption{WithDetectMoves()},
+		},
+		{
+			name: "json documents",
+			left: document{
+				Payload: json.RawMessage(`{"items": [{"price": 1}, {"price": 2}], "x": 1, "gone": true}`),
+				Doc:     `{"a": [1, 2], "b": "k"}`,
+			},
+			right: document{
+				Payload: json.RawMessage(`{"items":[{"price":1},{"price":3}],"x":1}`),
+				Doc:     `{"a":[1,3],"b":"k"}`,
+			},
+		},
+		{
+			name:  "json document root",
+			left:  json.RawMessage(`{"a": [1, 2], "b": "k"}`),
+			right: json.RawMessage(`{"a": [3], "c": "k"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			patch, err := result.ToJSONPatch()
+			if err != nil {
+				t.Fatalf("ToJSONPatch failed: %v", err)
+			}
+			expected := tt.expected
+			if expected == nil {
+				expected = tt.right
+			}
+			got, err := applyJSONPatch(jsonDecoded(t, tt.left), patch)
+			if err != nil {
+				t.Fatalf("Applying %+v failed: %v", patch, err)
+			}
+			if want := jsonDecoded(t, expected); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v, got %v after applying %+v", want, got, patch)
+			}
+		})
+	}
+}
+
+// jsonDecoded returns the decoded JSON encoding of v
+func jsonDecoded(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return doc
+}
+
+// applyJSONPatch applies the add, remove, replace, move and test operations of an
+// RFC 6902 JSON Patch to a decoded JSON document
+func applyJSONPatch(doc any, patch JSONPatch) (any, error) {
+	for _, op := range patch {
+		value, err := decodedValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case JSONPatchAdd, JSONPatchReplace:
+			doc, err = patchJSONPointer(doc, op.Path, op.Op, value)
+		case JSONPatchRemove:
+			doc, err = patchJSONPointer(doc, op.Path, op.Op, nil)
+		case JSONPatchMove:
+			var moved any
+			if moved, err = resolveJSONPointer(doc, op.From); err == nil {
+				if doc, err = patchJSONPointer(doc, op.From, JSONPatchRemove, nil); err == nil {
+					doc, err = patchJSONPointer(doc, op.Path, JSONPatchAdd, moved)
+				}
+			}
+		case JSONPatchTest:
+			var current any
+			if current, err = resolveJSONPointer(doc, op.Path); err == nil && !reflect.DeepEqual(current, value) {
+				err = fmt.Errorf("test failed at %q: %v != %v", op.Path, current, value)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// decodedValue returns the decoded JSON encoding of a patch value
+func decodedValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	err = json.Unmarshal(data, &decoded)
+	return decoded, err
+}
+
+// resolveJSONPointer walks a JSON Pointer through a decoded document
+func resolveJSONPointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch d := doc.(type) {
+		case map[string]any:
+			member, ok := d[token]
+			if !ok {
+				return nil, fmt.Errorf("no member %q in %q", token, pointer)
+			}
+			doc = member
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(d) {
+				return nil, fmt.Errorf("invalid index %q in %q", token, pointer)
+			}
+			doc = d[i]
+		default:
+			return nil, fmt.Errorf("cannot resolve %q in %q", token, pointer)
+		}
+	}
+	return doc, nil
+}
+
+// patchJSONPointer adds, replaces or removes the value a JSON Pointer refers to and
+// returns the patched document
+func patchJSONPointer(doc any, pointer, op string, value any) (any, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	token := strings.ReplaceAll(strings.ReplaceAll(pointer[len(parentPointer)+1:], "~1", "/"), "~0", "~")
+	parent, err := resolveJSONPointer(doc, parentPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched any
+	switch p := parent.(type) {
+	case map[string]any:
+		if _, ok := p[token]; !ok && op != JSONPatchAdd {
+			return nil, fmt.Errorf("no member %q at %q", token, pointer)
+		}
+		switch op {
+		case JSONPatchAdd, JSONPatchReplace:
+			p[token] = value
+		case JSONPatchRemove:
+			delete(p, token)
+		}
+		patched = p
+	case []any:
+		i := len(p)
+		if token != "-" {
+			if i, err = strconv.Atoi(token); err != nil || i < 0 || i > len(p) || i == len(p) && op != JSONPatchAdd {
+				return nil, fmt.Errorf("invalid index at %q", pointer)
+			}
+		}
+		switch op {
+		case JSONPatchAdd:
+			patched = slices.Insert(p, i, value)
+		case JSONPatchReplace:
+			p[i] = value
+			patched = p
+		case JSONPatchRemove:
+			patched = slices.Delete(p, i, i+1)
+		}
+	default:
+		return nil, fmt.Errorf("cannot patch %q", pointer)
+	}
+	return patchJSONPointer(doc, parentPointer, JSONPatchReplace, patched)
+}
@@ -0,0 +1,246 @@
+package godiff
+
+// editOp is the kind of a single step of an edit script
+type editOp int
+
+const (
+	editMatch  editOp = iota // element kept, present in both sequences
+	editDelete               // element only in the left sequence
+	editInsert               // element only in the right sequence
+)
+
+// edit is a single step of an edit script. left and right are the element indexes
+// in the left and right sequence; the index of the side an element is missing from
+// is the position it would have there.
+type edit struct {
+	op          editOp
+	left, right int
+}
+
+// shortestEdits returns a shortest edit script turning a left sequence of length n
+// into a right sequence of length m, using Myers' O(ND) algorithm in linear space.
+// equal reports whether left element i equals right element j.
+func shortestEdits(n, m int, equal func(i, j int) bool) []edit {
+	edits, _ := boundedEdits(n, m, -1, equal)
+	return edits
+}
+
+// boundedEdits is shortestEdits for scripts of at most maxD deletions and insertions.
+// It gives up, reporting false, as soon as the search shows that more are needed, so
+// its cost is bounded by O((n+m) maxD). A negative maxD means no bound.
+func boundedEdits(n, m, maxD int, equal func(i, j int) bool) ([]edit, bool) {
+	// common prefix and suffix are matched without searching
+	prefix := 0
+	for prefix < n && prefix < m && equal(prefix, prefix) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && equal(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+	if maxD >= 0 && editDistanceExceeds(prefix, n-suffix, prefix, m-suffix, maxD, equal) {
+		return nil, false
+	}
+
+	s := &myersSearch{equal: equal, edits: make([]edit, 0, max(n, m))}
+	for i := range prefix {
+		s.edits = append(s.edits, edit{op: editMatch, left: i, right: i})
+	}
+	s.compare(prefix, n-suffix, prefix, m-suffix)
+	for i := suffix; i > 0; i-- {
+		s.edits = append(s.edits, edit{op: editMatch, left: n - i, right: m - i})
+	}
+	return deletionsFirst(s.edits), true
+}
+
+// deletionsFirst reorders every run of deletions and insertions between two matches so
+// that the deletions come first
+func deletionsFirst(edits []edit) []edit {
+	for i := 0; i < len(edits); {
+		if edits[i].op == editMatch {
+			i++
+			continue
+		}
+		x, y := edits[i].left, edits[i].right
+		end := i
+		for end < len(edits) && edits[end].op != editMatch {
+			end++
+		}
+		deleted := 0
+		for _, e := range edits[i:end] {
+			if e.op == editDelete {
+				deleted++
+			}
+		}
+		for k := range end - i {
+			if k < deleted {
+				edits[i+k] = edit{op: editDelete, left: x + k, right: y}
+			} else {
+				edits[i+k] = edit{op: editInsert, left: x + deleted, right: y + k - deleted}
+			}
+		}
+		i = end
+	}
+	return edits
+}
+
+// editDistanceExceeds reports whether turning left[x0:x1] into right[y0:y1] takes more
+// than maxD deletions and insertions. It runs the forward Myers search, keeping only
+// the current frontier.
+func editDistanceExceeds(x0, x1, y0, y1, maxD int, equal func(i, j int) bool) bool {
+	n, m := x1-x0, y1-y0
+	if n+m <= maxD {
+		return false
+	}
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && equal(x0+x, y0+y) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// myersSearch builds an edit script by recursively splitting the edit graph at a point
+// on a shortest path, found by searching from both ends until the searches meet
+type myersSearch struct {
+	equal    func(i, j int) bool
+	edits    []edit
+	fwd, bwd []int // frontiers of the forward and backward search, reused across splits
+}
+
+// compare appends the edits turning left[x0:x1] into right[y0:y1]
+func (s *myersSearch) compare(x0, x1, y0, y1 int) {
+	start := x0
+	for x0 < x1 && y0 < y1 && s.equal(x0, y0) {
+		x0++
+		y0++
+	}
+	for i := start; i < x0; i++ {
+		s.edits = append(s.edits, edit{op: editMatch, left: i, right: y0 - (x0 - i)})
+	}
+	suffix := 0
+	for x0 < x1-suffix && y0 < y1-suffix && s.equal(x1-1-suffix, y1-1-suffix) {
+		suffix++
+	}
+	x1, y1 = x1-suffix, y1-suffix
+
+	switch {
+	case x0 == x1:
+		for j := y0; j < y1; j++ {
+			s.edits = append(s.edits, edit{op: editInsert, left: x0, right: j})
+		}
+	case y0 == y1:
+		for i := x0; i < x1; i++ {
+			s.edits = append(s.edits, edit{op: editDelete, left: i, right: y0})
+		}
+	default:
+		if x, y, ok := s.split(x0, x1, y0, y1); ok {
+			s.compare(x0, x, y0, y)
+			s.compare(x, x1, y, y1)
+		} else {
+			// nothing in common
+			for i := x0; i < x1; i++ {
+				s.edits = append(s.edits, edit{op: editDelete, left: i, right: y0})
+			}
+			for j := y0; j < y1; j++ {
+				s.edits = append(s.edits, edit{op: editInsert, left: x1, right: j})
+			}
+		}
+	}
+
+	for i := suffix; i > 0; i-- {
+		s.edits = append(s.edits, edit{op: editMatch, left: x1 + suffix - i, right: y1 + suffix - i})
+	}
+}
+
+// split returns a point on a shortest path through the edit graph of left[x0:x1] and
+// right[y0:y1], which must differ in their first and last elements. It reports false
+// if the sequences have no element in common.
+func (s *myersSearch) split(x0, x1, y0, y1 int) (x, y int, ok bool) {
+	n, m := x1-x0, y1-y0
+	maxD := (n + m + 1) / 2
+	offset, size := maxD, 2*maxD+2
+	if cap(s.fwd) < size {
+		s.fwd, s.bwd = make([]int, size), make([]int, size)
+	}
+	fwd, bwd := s.fwd[:size], s.bwd[:size]
+	for i := range size {
+		fwd[i], bwd[i] = -1, -1
+	}
+	fwd[offset+1], bwd[offset+1] = 0, 0
+
+	delta := n - m
+	// with an odd delta the forward search reaches the overlap first
+	front := delta%2 != 0
+	// diagonals that left the graph are no longer searched
+	fwdStart, fwdEnd, bwdStart, bwdEnd := 0, 0, 0, 0
+	for d := range maxD {
+		for k := -d + fwdStart; k <= d-fwdEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && fwd[offset+k-1] < fwd[offset+k+1]) {
+				x = fwd[offset+k+1]
+			} else {
+				x = fwd[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.equal(x0+x, y0+y) {
+				x++
+				y++
+			}
+			fwd[offset+k] = x
+			switch {
+			case x > n:
+				fwdEnd += 2
+			case y > m:
+				fwdStart += 2
+			case front:
+				if c := offset + delta - k; c >= 0 && c < size && bwd[c] != -1 && x >= n-bwd[c] {
+					return x0 + x, y0 + y, true
+				}
+			}
+		}
+
+		for k := -d + bwdStart; k <= d-bwdEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && bwd[offset+k-1] < bwd[offset+k+1]) {
+				x = bwd[offset+k+1]
+			} else {
+				x = bwd[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && s.equal(x1-1-x, y1-1-y) {
+				x++
+				y++
+			}
+			bwd[offset+k] = x
+			switch {
+			case x > n:
+				bwdEnd += 2
+			case y > m:
+				bwdStart += 2
+			case !front:
+				if c := offset + delta - k; c >= 0 && c < size && fwd[c] != -1 && fwd[c] >= n-x {
+					fx := fwd[c]
+					return x0 + fx, y0 + fx - (c - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
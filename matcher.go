@@ -0,0 +1,265 @@
+package godiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pathMatcher matches rendered paths against the patterns of CompareConfig.IgnoreFields.
+// Plain patterns are looked up in a set, wildcard and regex patterns are compiled once.
+type pathMatcher struct {
+	exact    map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// Regular expressions for the rendered path tokens a wildcard pattern can stand for
+const (
+	fieldTokenPattern   = `[^.\[]`
+	bracketTokenPattern = `\[(?:"(?:[^"\\]|\\.)*"|[^\]"]*)\]`
+	anyTokensPattern    = `(?:(?:^|\.)` + fieldTokenPattern + `+|` + bracketTokenPattern + `)*`
+	bracketWildcard     = `(?:"(?:[^"\\]|\\.)*"|[^\]"])*`
+)
+
+// newPathMatcher compiles ignore patterns. Patterns prefixed with `re:` are regular
+// expressions matched against the rendered path; patterns containing `*` or `?` are
+// wildcards, where `*` and `?` match within a field name or bracket, `[*]` matches
+// any index, map key or identity step and `**` matches any number of steps.
+func newPathMatcher(patterns []string) (*pathMatcher, error) {
+	m := &pathMatcher{exact: make(map[string]bool, len(patterns))}
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasPrefix(pattern, "re:"):
+			re, err := regexp.Compile(strings.TrimPrefix(pattern, "re:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+			}
+			m.patterns = append(m.patterns, re)
+		case strings.ContainsAny(pattern, "*?"):
+			expr, err := wildcardExpression(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+			}
+			m.patterns = append(m.patterns, regexp.MustCompile(expr))
+		default:
+			m.exact[pattern] = true
+		}
+	}
+	return m, nil
+}
+
+// match reports whether a rendered path matches any of the patterns
+func (m *pathMatcher) match(path string) bool {
+	if m.exact[path] {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardExpression translates a wildcard pattern into an anchored regular expression
+// over the paths rendered by Path.String
+func wildcardExpression(pattern string) (string, error) {
+	tokens, err := splitPattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteByte('^')
+	for i, token := range tokens {
+		switch {
+		case token == "**":
+			sb.WriteString(anyTokensPattern)
+		case strings.HasPrefix(token, "["):
+			writeTokenExpression(&sb, token)
+		default:
+			if i > 0 {
+				sb.WriteString(`(?:^|\.)`)
+			}
+			writeTokenExpression(&sb, token)
+		}
+	}
+	sb.WriteByte('$')
+	return sb.String(), nil
+}
+
+// splitPattern splits a path pattern into its field names and bracketed steps
+func splitPattern(pattern string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '.':
+			if i == 0 || i+1 >= len(pattern) || pattern[i+1] == '.' || pattern[i+1] == '[' {
+				return nil, fmt.Errorf("empty field name at offset %d", i)
+			}
+			i++
+		case '[':
+			end := bracketEnd(pattern, i)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket at offset %d", i)
+			}
+			tokens = append(tokens, pattern[i:end+1])
+			i = end + 1
+		default:
+			end := strings.IndexAny(pattern[i:], ".[")
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			tokens = append(tokens, pattern[i:i+end])
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+// writeTokenExpression writes the regular expression for a single field name or bracketed
+// step of a pattern
+func writeTokenExpression(sb *strings.Builder, token string) {
+	switch {
+	case token == "[*]":
+		sb.WriteString(bracketTokenPattern)
+	case strings.HasPrefix(token, "["):
+		sb.WriteString(`\[`)
+		writeWildcard(sb, token[1:len(token)-1], bracketWildcard, `[^\]"]`)
+		sb.WriteString(`\]`)
+	default:
+		writeWildcard(sb, token, fieldTokenPattern+"*", fieldTokenPattern)
+	}
+}
+
+// allowMatcher matches paths against the patterns of CompareConfig.OnlyFields. Unlike
+// pathMatcher it also recognizes the ancestors of allowed paths, which must be descended into.
+type allowMatcher struct {
+	patterns [][]patternToken
+}
+
+// patternToken is a single step of an allow-list pattern
+type patternToken struct {
+	literal string
+	re      *regexp.Regexp // nil for literal tokens
+	any     bool           // `**`, matching any number of steps
+}
+
+// newAllowMatcher compiles allow-list patterns, which use the wildcard syntax of IgnoreFields
+func newAllowMatcher(patterns []string) (*allowMatcher, error) {
+	m := &allowMatcher{}
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "re:") {
+			return nil, fmt.Errorf("invalid allow pattern %q: regular expressions are not supported", pattern)
+		}
+		tokens, err := splitPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+		compiled := make([]patternToken, len(tokens))
+		for i, token := range tokens {
+			switch {
+			case token == "**":
+				compiled[i].any = true
+			case strings.ContainsAny(token, "*?"):
+				var sb strings.Builder
+				sb.WriteByte('^')
+				writeTokenExpression(&sb, token)
+				sb.WriteByte('$')
+				compiled[i].re = regexp.MustCompile(sb.String())
+			default:
+				compiled[i].literal = token
+			}
+		}
+		m.patterns = append(m.patterns, compiled)
+	}
+	return m, nil
+}
+
+// match reports whether path is at or below an allowed path, and whether it is an
+// ancestor of one
+func (m *allowMatcher) match(path Path) (allowed, ancestor bool) {
+	tokens := make([]string, 0, len(path))
+	for _, step := range path {
+		if step.Kind != StepPointer && step.Kind != StepInterface {
+			tokens = append(tokens, Path{step}.String())
+		}
+	}
+	for _, pattern := range m.patterns {
+		a, d := matchTokens(pattern, tokens)
+		allowed = allowed || a
+		ancestor = ancestor || d
+		if allowed {
+			return allowed, ancestor
+		}
+	}
+	return allowed, ancestor
+}
+
+// excludes reports whether a change lies outside the allowed paths
+func (m *allowMatcher) excludes(change Change) bool {
+	allowed, _ := m.match(change.Location())
+	return !allowed
+}
+
+// matchTokens matches path tokens against a pattern, reporting whether the pattern matches
+// a prefix of the path and whether the path matches a prefix of the pattern
+func matchTokens(pattern []patternToken, tokens []string) (allowed, ancestor bool) {
+	if len(pattern) == 0 {
+		return true, false
+	}
+	if pattern[0].any {
+		allowed, ancestor = matchTokens(pattern[1:], tokens)
+		if !allowed && len(tokens) > 0 {
+			a, d := matchTokens(pattern, tokens[1:])
+			allowed, ancestor = a, ancestor || d
+		}
+		return allowed, ancestor || len(tokens) == 0
+	}
+	if len(tokens) == 0 {
+		return false, true
+	}
+	if !pattern[0].matches(tokens[0]) {
+		return false, false
+	}
+	return matchTokens(pattern[1:], tokens[1:])
+}
+
+// matches reports whether a rendered path token matches the pattern token
+func (t patternToken) matches(token string) bool {
+	if t.re != nil {
+		return t.re.MatchString(token)
+	}
+	return t.literal == token
+}
+
+// bracketEnd returns the index of the `]` closing the bracket opened at start, skipping
+// quoted keys, or -1 if the bracket is not terminated
+func bracketEnd(s string, start int) int {
+	inQuote := false
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case inQuote && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inQuote = !inQuote
+		case s[i] == ']' && !inQuote:
+			return i
+		}
+	}
+	return -1
+}
+
+// writeWildcard writes s with `*` and `?` replaced by the given expressions and all
+// other characters quoted
+func writeWildcard(sb *strings.Builder, s, star, question string) {
+	for _, part := range strings.SplitAfter(s, "") {
+		switch part {
+		case "*":
+			sb.WriteString(star)
+		case "?":
+			sb.WriteString(question)
+		default:
+			sb.WriteString(regexp.QuoteMeta(part))
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ToMergePatch converts the diff result into an RFC 7386 JSON Merge Patch document.
+// Changed struct fields and map entries become nested members, removed map entries and
+// values that became nil become null, and because merge patches cannot address array
+// elements, any change inside a slice replaces the whole slice with its right-side value.
+// Changes inside a JSON document held in a string set the whole right-side string; inside
+// a json.RawMessage they become the merge patch between the decoded documents.
+func (dr *DiffResult) ToMergePatch() ([]byte, error) {
+	if len(dr.Diffs) == 0 {
+		return []byte(`{}`), nil
+	}
+
+	patch := make(map[string]any)
+	for _, diff := range dr.Diffs {
+		var steps Path
+		var value any
+		var slicePath bool
+		switch d := diff.(type) {
+		case *MapDiff:
+			steps, value = valueSteps(&d.Diff), d.Right
+		case *SliceDiff:
+			steps, slicePath = containerSteps(d), true
+		case *StructDiff:
+			steps, value = valueSteps(&d.Diff), d.Right
+		case *Diff:
+			steps, value = valueSteps(d), d.Right
+			container := lookupPath(reflect.ValueOf(dr.left), steps)
+			slicePath = isSliceElementChange(container, d.Left, d.Right)
+		default:
+			return nil, fmt.Errorf("cannot convert unknown diff type %T to a merge patch", diff)
+		}
+
+		document, inDocument := dr.jsonDocumentSteps(steps)
+		if inDocument {
+			steps, slicePath = document, false
+			var err error
+			if value, err = dr.jsonDocumentMergePatch(document); err != nil {
+				return nil, err
+			}
+		}
+
+		tokens, omitted := dr.jsonTokens(steps)
+		if omitted {
+			continue
+		}
+
+		// Arrays are replaced as a whole: cut the path at the outermost array
+		// and take the complete right-side value found there.
+		for i, token := range tokens {
+			if token.array {
+				tokens, steps, slicePath = tokens[:i], steps[:token.start], true
+				break
+			}
+		}
+		if slicePath {
+			v := lookupPath(reflect.ValueOf(dr.right), steps)
+			if !v.IsValid() {
+				return nil, fmt.Errorf("cannot resolve right-side value at %q", steps)
+			}
+			value = v.Interface()
+		}
+
+		if len(tokens) == 0 {
+			// the document root itself changed, so the patch is the new document
+			if inDocument {
+				return json.Marshal(value)
+			}
+			return json.Marshal(dr.right)
+		}
+
+		obj := patch
+		for _, token := range tokens[:len(tokens)-1] {
+			child, ok := obj[token.name].(map[string]any)
+			if !ok {
+				if _, exists := obj[token.name]; exists {
+					// an enclosing value is already replaced as a whole
+					obj = nil
+					break
+				}
+				child = make(map[string]any)
+				obj[token.name] = child
+			}
+			obj = child
+		}
+		if obj != nil {
+			obj[tokens[len(tokens)-1].name] = value
+		}
+	}
+
+	return json.Marshal(patch)
+}
+
+// jsonDocumentMergePatch returns the merge patch value of the JSON document at path: the
+// right-side string for documents held in strings, which are encoded as JSON strings, and
+// the merge patch between the decoded documents for json.RawMessage values
+func (dr *DiffResult) jsonDocumentMergePatch(path Path) (any, error) {
+	left := indirectValue(lookupPath(reflect.ValueOf(dr.left), path))
+	right := indirectValue(lookupPath(reflect.ValueOf(dr.right), path))
+	if !left.IsValid() || !right.IsValid() {
+		return nil, fmt.Errorf("cannot resolve JSON document at %q", path)
+	}
+	if right.Kind() == reflect.String {
+		return right.Interface(), nil
+	}
+	leftDoc, rightDoc, ok := decodeJSONPair(jsonDocument(left), jsonDocument(right))
+	if !ok {
+		return right.Interface(), nil
+	}
+	return jsonMergePatch(leftDoc, rightDoc), nil
+}
+
+// jsonMergePatch returns the merge patch turning the decoded JSON value left into right:
+// objects are patched member by member, with null for removed members, everything else
+// is replaced
+func jsonMergePatch(left, right any) any {
+	l, leftIsObject := left.(map[string]any)
+	r, rightIsObject := right.(map[string]any)
+	if !leftIsObject || !rightIsObject {
+		return right
+	}
+	patch := make(map[string]any)
+	for name, leftMember := range l {
+		rightMember, ok := r[name]
+		switch {
+		case !ok:
+			patch[name] = nil
+		case !reflect.DeepEqual(leftMember, rightMember):
+			patch[name] = jsonMergePatch(leftMember, rightMember)
+		}
+	}
+	for name, rightMember := range r {
+		if _, ok := l[name]; !ok {
+			patch[name] = rightMember
+		}
+	}
+	return patch
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch document to target, which must be
+// a non-nil pointer. Objects in the patch are merged recursively into structs (matched by
+// json tag names), maps, pointers and interfaces; null members reset struct fields to their
+// zero value and delete map entries; all other patch values replace the target value.
+// Objects are merged into the documents held by json.RawMessage values as well.
+func ApplyMergePatch(target any, patch []byte) error {
+	rootPtr := reflect.ValueOf(target)
+	if rootPtr.Kind() != reflect.Pointer || rootPtr.IsNil() {
+		return fmt.Errorf("merge patch target must be a non-nil pointer, got %T", target)
+	}
+
+	var doc any
+	if err := json.Unmarshal(patch, &doc); err != nil {
+		return fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	root := rootPtr.Elem()
+	nv, err := mergeValue(root, doc, "")
+	if err != nil {
+		return err
+	}
+	root.Set(nv)
+	return nil
+}
+
+// mergeValue merges patch into cur and returns the resulting value
+func mergeValue(cur reflect.Value, patch any, path string) (reflect.Value, error) {
+	obj, isObject := patch.(map[string]any)
+	if !isObject {
+		return decodeJSONValue(patch, cur.Type(), path)
+	}
+	if cur.Type() == reflect.TypeFor[json.RawMessage]() {
+		return mergeRawMessage(cur, obj, path)
+	}
+
+	switch cur.Kind() {
+	case reflect.Pointer:
+		ptr := cur
+		if ptr.IsNil() {
+			ptr = reflect.New(cur.Type().Elem())
+		}
+		nv, err := mergeValue(ptr.Elem(), obj, path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr.Elem().Set(nv)
+		return ptr, nil
+	case reflect.Interface:
+		// objects merged into anything but a map, struct or pointer replace it
+		inner := reflect.ValueOf(map[string]any{})
+		if !cur.IsNil() && slices.Contains([]reflect.Kind{reflect.Map, reflect.Struct, reflect.Pointer}, cur.Elem().Kind()) {
+			inner = addressableCopy(cur.Elem())
+		}
+		return mergeValue(inner, obj, path)
+	case reflect.Struct:
+		return mergeStruct(addressableCopy(cur), obj, path)
+	case reflect.Map:
+		return mergeMap(cur, obj, path)
+	default:
+		return decodeJSONValue(stripNulls(obj), cur.Type(), path)
+	}
+}
+
+// mergeRawMessage merges obj into the JSON document held by the json.RawMessage raw. An
+// empty or invalid document is replaced.
+func mergeRawMessage(raw reflect.Value, obj map[string]any, path string) (reflect.Value, error) {
+	var doc any
+	if json.Unmarshal(raw.Bytes(), &doc) != nil {
+		doc = nil
+	}
+	merged, err := mergeValue(reflect.ValueOf(&doc).Elem(), obj, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	encoded, err := json.Marshal(merged.Interface())
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot encode JSON document at %q: %w", path, err)
+	}
+	return reflect.ValueOf(json.RawMessage(encoded)), nil
+}
+
+// mergeStruct merges the members of obj into the fields of the settable struct s
+func mergeStruct(s reflect.Value, obj map[string]any, path string) (reflect.Value, error) {
+	for _, field := range reflect.VisibleFields(s.Type()) {
+		if !field.IsExported() {
+			continue
+		}
+		name, omit := jsonFieldName(field)
+		if omit || name == "" {
+			continue
+		}
+		member, ok := obj[name]
+		if !ok {
+			continue
+		}
+
+		fieldVal, err := settableField(s, field.Index)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w at %q", err, joinPath(path, field.Name))
+		}
+		if member == nil {
+			fieldVal.Set(reflect.Zero(field.Type))
+			continue
+		}
+		nv, err := mergeValue(fieldVal, member, joinPath(path, field.Name))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		fieldVal.Set(nv)
+	}
+	return s, nil
+}
+
+// mergeMap merges the members of obj into the entries of m
+func mergeMap(m reflect.Value, obj map[string]any, path string) (reflect.Value, error) {
+	if m.IsNil() {
+		m = reflect.MakeMap(m.Type())
+	}
+	for name, member := range obj {
+		elementPath := path + "[" + name + "]"
+		key, err := decodeMapKey(name, m.Type().Key())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%w at %q", err, elementPath)
+		}
+		if member == nil {
+			m.SetMapIndex(key, reflect.Value{})
+			continue
+		}
+
+		current := reflect.New(m.Type().Elem()).Elem()
+		if existing := m.MapIndex(key); existing.IsValid() {
+			current.Set(existing)
+		}
+		nv, err := mergeValue(current, member, elementPath)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m.SetMapIndex(key, nv)
+	}
+	return m, nil
+}
+
+// settableField returns the field of s at index, allocating nil embedded pointers on the way
+func settableField(s reflect.Value, index []int) (reflect.Value, error) {
+	v := s
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate embedded %s", v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// decodeMapKey converts a JSON object member name into a map key of type typ
+func decodeMapKey(name string, typ reflect.Type) (reflect.Value, error) {
+	if typ.Kind() == reflect.String {
+		return reflect.ValueOf(name).Convert(typ), nil
+	}
+	key := reflect.New(typ)
+	if err := json.Unmarshal([]byte(name), key.Interface()); err != nil {
+		if err := json.Unmarshal([]byte(`"`+name+`"`), key.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot use %q as %s map key", name, typ)
+		}
+	}
+	return key.Elem(), nil
+}
+
+// decodeJSONValue converts a decoded JSON value into a value of type typ
+func decodeJSONValue(value any, typ reflect.Type, path string) (reflect.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot encode merge patch value at %q: %w", path, err)
+	}
+	nv := reflect.New(typ)
+	if err := json.Unmarshal(data, nv.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot decode merge patch value at %q: %w", path, err)
+	}
+	return nv.Elem(), nil
+}
+
+// stripNulls removes null members from obj and its nested objects, as RFC 7386 does
+// when an object is merged into a non-object target
+func stripNulls(obj map[string]any) map[string]any {
+	out := make(map[string]any, len(obj))
+	for name, member := range obj {
+		switch m := member.(type) {
+		case nil:
+		case map[string]any:
+			out[name] = stripNulls(m)
+		default:
+			out[name] = m
+		}
+	}
+	return out
+}
+
+// joinPath appends a field name to a diff path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
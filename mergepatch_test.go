@@ -0,0 +1,205 @@
+package godiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type mergeConfig struct {
+	PatchBase
+	Name     string            `json:"name"`
+	Port     int               `json:"port,omitempty"`
+	Secret   string            `json:"-"`
+	Hosts    []string          `json:"hosts"`
+	Labels   map[string]string `json:"labels"`
+	Limits   map[int]float64   `json:"limits"`
+	Owner    *Address          `json:"owner"`
+	Servers  []Address         `json:"servers"`
+	Settings map[string]any    `json:"settings"`
+}
+
+func TestToMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		expected string
+	}{
+		{
+			name:     "nested fields use json tags",
+			left:     mergeConfig{Name: "a", Owner: &Address{City: "Berlin"}, Secret: "x"},
+			right:    mergeConfig{Name: "b", Owner: &Address{City: "Paris"}, Secret: "y"},
+			expected: `{"name":"b","owner":{"City":"Paris"}}`,
+		},
+		{
+			name:     "removed map entry is null",
+			left:     mergeConfig{Labels: map[string]string{"env": "prod"}},
+			right:    mergeConfig{Labels: map[string]string{}},
+			expected: `{"labels":{"env":null}}`,
+		},
+		{
+			name:     "slice change replaces the slice",
+			left:     mergeConfig{Servers: []Address{{City: "A"}, {City: "B"}}},
+			right:    mergeConfig{Servers: []Address{{City: "A"}, {City: "C"}}},
+			expected: `{"servers":[{"Street":"","City":"A","Country":""},{"Street":"","City":"C","Country":""}]}`,
+		},
+		{
+			name:     "embedded fields are promoted",
+			left:     mergeConfig{PatchBase: PatchBase{ID: 1}},
+			right:    mergeConfig{PatchBase: PatchBase{ID: 2}},
+			expected: `{"id":2}`,
+		},
+		{
+			name:     "pointer becomes nil",
+			left:     mergeConfig{Owner: &Address{City: "Berlin"}},
+			right:    mergeConfig{},
+			expected: `{"owner":null}`,
+		},
+		{
+			name:     "root slice",
+			left:     []int{1, 2},
+			right:    []int{1, 3},
+			expected: `[1,3]`,
+		},
+		{
+			name:     "no differences",
+			left:     mergeConfig{Name: "a"},
+			right:    mergeConfig{Name: "a"},
+			expected: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			patch, err := result.ToMergePatch()
+			if err != nil {
+				t.Fatalf("ToMergePatch failed: %v", err)
+			}
+			if string(patch) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, patch)
+			}
+		})
+	}
+}
+
+func TestMergePatchRoundTrip(t *testing.T) {
+	left := mergeConfig{
+		PatchBase: PatchBase{ID: 1},
+		Name:      "svc",
+		Port:      80,
+		Hosts:     []string{"a", "b"},
+		Labels:    map[string]string{"env": "prod", "team": "x"},
+		Limits:    map[int]float64{1: 0.5},
+		Owner:     &Address{City: "Berlin"},
+		Settings:  map[string]any{"debug": false, "nested": map[string]any{"level": 1.0, "gone": "x"}},
+	}
+	right := mergeConfig{
+		PatchBase: PatchBase{ID: 2},
+		Name:      "svc",
+		Hosts:     []string{"b"},
+		Labels:    map[string]string{"env": "dev"},
+		Limits:    map[int]float64{1: 0.75, 2: 1},
+		Servers:   []Address{{City: "Paris"}},
+		Settings:  map[string]any{"debug": true, "nested": map[string]any{"level": 2.0}},
+	}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	patch, err := result.ToMergePatch()
+	if err != nil {
+		t.Fatalf("ToMergePatch failed: %v", err)
+	}
+
+	if err := ApplyMergePatch(&left, patch); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	after, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if after.HasDifferences() {
+		t.Errorf("Expected no differences after merge patch %s, got %s", patch, after.String())
+	}
+}
+
+func TestMergePatchJSONDocuments(t *testing.T) {
+	type document struct {
+		Payload json.RawMessage `json:"payload"`
+		Doc     string          `json:"doc" diff:"json"`
+	}
+	left := document{
+		Payload: json.RawMessage(`{"items": [{"price": 1}, {"price": 2}], "x": 1, "gone": true}`),
+		Doc:     `{"a": [1, 2], "b": "k"}`,
+	}
+	right := document{
+		Payload: json.RawMessage(`{"items":[{"price":1},{"price":3}],"x":1,"new":{"y":2}}`),
+		Doc:     `{"a":[1,3],"b":"k"}`,
+	}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	patch, err := result.ToMergePatch()
+	if err != nil {
+		t.Fatalf("ToMergePatch failed: %v", err)
+	}
+	expected := `{"doc":"{\"a\":[1,3],\"b\":\"k\"}","payload":{"gone":null,"items":[{"price":1},{"price":3}],"new":{"y":2}}}`
+	if string(patch) != expected {
+		t.Errorf("Expected %s, got %s", expected, patch)
+	}
+	if err := ApplyMergePatch(&left, patch); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	if after, err := Compare(left, right); err != nil || after.HasDifferences() {
+		t.Errorf("Expected no differences after merge patch %s, got %v %v", patch, after, err)
+	}
+
+	leftRaw, rightRaw := json.RawMessage(`{"a": {"b": 1, "c": 2}}`), json.RawMessage(`{"a": {"b": 1}, "d": [1]}`)
+	result, err = Compare(leftRaw, rightRaw)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if patch, err = result.ToMergePatch(); err != nil {
+		t.Fatalf("ToMergePatch failed: %v", err)
+	}
+	if err := ApplyMergePatch(&leftRaw, patch); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	if after, err := Compare(leftRaw, rightRaw); err != nil || after.HasDifferences() {
+		t.Errorf("Expected no differences after root merge patch %s, got %v %v", patch, after, err)
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	target := map[string]any{"a": "b", "c": map[string]any{"d": "e", "f": "g"}}
+	if err := ApplyMergePatch(&target, []byte(`{"a":"z","c":{"f":null},"n":{"x":null,"y":1}}`)); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	expected := map[string]any{"a": "z", "c": map[string]any{"d": "e"}, "n": map[string]any{"y": 1.0}}
+	if !reflect.DeepEqual(target, expected) {
+		t.Errorf("Expected %v, got %v", expected, target)
+	}
+
+	var count int
+	if err := ApplyMergePatch(&count, []byte(`5`)); err != nil || count != 5 {
+		t.Errorf("Expected root replacement to 5, got %d (%v)", count, err)
+	}
+
+	if err := ApplyMergePatch(target, []byte(`{}`)); err == nil {
+		t.Error("Expected error for non-pointer target")
+	}
+	if err := ApplyMergePatch(&target, []byte(`{`)); err == nil {
+		t.Error("Expected error for invalid patch")
+	}
+	if err := ApplyMergePatch(&count, []byte(`"x"`)); err == nil {
+		t.Error("Expected error for mismatched value type")
+	}
+}
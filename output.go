@@ -1,8 +1,10 @@
 package godiff
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -18,99 +20,98 @@ func (dr *DiffResult) String() string {
 
 	sb.WriteString("Found ")
 	sb.WriteString(strconv.Itoa(len(dr.Diffs)))
-	sb.WriteString(" differences:\n")
+	sb.WriteString(" differences")
+	switch {
+	case dr.Truncated && dr.EstimatedTotal > 0:
+		sb.WriteString(" (truncated, about ")
+		sb.WriteString(strconv.Itoa(dr.EstimatedTotal))
+		sb.WriteString(" in total)")
+	case dr.Truncated:
+		sb.WriteString(" (truncated)")
+	}
+	sb.WriteString(":\n")
 
 	for _, diff := range dr.Diffs {
-		switch d := diff.(type) {
-		case *MapDiff:
-			sb.WriteString(string(d.ChangeType))
-			sb.WriteString(" ")
-			sb.WriteString(d.Path)
-			sb.WriteString(": ")
-			switch d.ChangeType {
-			case ChangeTypeAdded:
-				fmt.Fprint(&sb, d.Right)
-			case ChangeTypeRemoved:
-				fmt.Fprint(&sb, d.Left)
-			default:
-				fmt.Fprint(&sb, d.Left)
-				sb.WriteString(" -> ")
-				fmt.Fprint(&sb, d.Right)
-			}
-			sb.WriteString("\n")
+		d := diff.base()
+		sb.WriteString(string(diff.Kind()))
+		switch c := diff.(type) {
 		case *SliceDiff:
-			sb.WriteString(string(d.ChangeType))
 			sb.WriteString(" ")
-			sb.WriteString(d.Path)
-			sb.WriteString("[")
-			sb.WriteString(strconv.Itoa(d.Index))
-			sb.WriteString("]: ")
-			switch d.ChangeType {
-			case ChangeTypeAdded:
-				fmt.Fprint(&sb, d.Right)
-			case ChangeTypeRemoved:
-				fmt.Fprint(&sb, d.Left)
-			default:
-				fmt.Fprint(&sb, d.Left)
-				sb.WriteString(" -> ")
-				fmt.Fprint(&sb, d.Right)
+			if _, last := c.Steps.Parent(); last.Kind == StepIdentity {
+				sb.WriteString(c.Steps.String())
+			} else {
+				sb.WriteString(d.Path)
+				sb.WriteString("[")
+				sb.WriteString(strconv.Itoa(c.Index))
+				sb.WriteString("]")
 			}
-			sb.WriteString("\n")
 		case *StructDiff:
-			sb.WriteString(string(d.ChangeType))
-			if d.FieldName != "" {
+			if c.FieldName != "" {
 				sb.WriteString(" ")
-				if d.Path == d.FieldName {
-					sb.WriteString(d.FieldName)
-					sb.WriteString(": ")
-				} else {
-					pathParts := strings.Split(d.Path, ".")
-					if len(pathParts) > 1 && pathParts[len(pathParts)-1] == d.FieldName {
-						parentPath := strings.Join(pathParts[:len(pathParts)-1], ".")
-						if parentPath == "" {
-							sb.WriteString(d.FieldName)
-							sb.WriteString(": ")
-						} else {
-							sb.WriteString(parentPath)
-							sb.WriteString(".")
-							sb.WriteString(d.FieldName)
-							sb.WriteString(": ")
-						}
-					} else {
-						sb.WriteString(d.Path)
-						sb.WriteString(": ")
-					}
-				}
-			} else {
-				sb.WriteString(": ")
-			}
-			switch d.ChangeType {
-			case ChangeTypeAdded:
-				fmt.Fprint(&sb, d.Right)
-			case ChangeTypeRemoved:
-				fmt.Fprint(&sb, d.Left)
-			default:
-				fmt.Fprint(&sb, d.Left)
-				sb.WriteString(" -> ")
-				fmt.Fprint(&sb, d.Right)
+				sb.WriteString(d.Path)
 			}
-			sb.WriteString("\n")
-		case *Diff:
-			sb.WriteString("UPDATED ")
+		default:
+			sb.WriteString(" ")
 			sb.WriteString(d.Path)
+		}
+		switch {
+		case d.Edits != nil:
 			sb.WriteString(": ")
-			fmt.Fprint(&sb, d.Left)
-			sb.WriteString(" -> ")
-			fmt.Fprint(&sb, d.Right)
+			sb.WriteString(d.Edits.String())
 			sb.WriteString("\n")
+			continue
+		case d.Text == nil:
+			sb.WriteString(": ")
+		default:
+			sb.WriteString(":\n")
+			for line := range strings.Lines(d.Text.String()) {
+				sb.WriteString("  ")
+				sb.WriteString(line)
+			}
+			continue
+		}
+		switch diff.Kind() {
+		case ChangeTypeMoved:
+			writeValue(&sb, d.Right)
+			if c, ok := diff.(*SliceDiff); ok {
+				sb.WriteString(" (from index ")
+				sb.WriteString(strconv.Itoa(c.FromIndex))
+				sb.WriteString(")")
+			}
+		case ChangeTypeAdded:
+			writeValue(&sb, d.Right)
+		case ChangeTypeRemoved:
+			writeValue(&sb, d.Left)
 		default:
-			sb.WriteString("? Unknown diff type\n")
+			writeValue(&sb, d.Left)
+			sb.WriteString(" -> ")
+			writeValue(&sb, d.Right)
 		}
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
 
+// writeValue writes a value of a change. JSON documents are written as text and other
+// byte slices as hex.
+func writeValue(sb *strings.Builder, v any) {
+	switch b := v.(type) {
+	case json.RawMessage:
+		sb.Write(b)
+		return
+	case fmt.Stringer:
+		fmt.Fprint(sb, b)
+		return
+	}
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 && !val.IsNil() {
+		sb.WriteString("0x")
+		sb.WriteString(hex.EncodeToString(val.Bytes()))
+		return
+	}
+	fmt.Fprint(sb, v)
+}
+
 // HasDifferences returns true if there are any differences
 func (dr *DiffResult) HasDifferences() bool {
 	return len(dr.Diffs) > 0
@@ -128,67 +129,48 @@ func (dr *DiffResult) ToJSON() string {
 	}
 
 	type jsonChange struct {
-		Type      string `json:"type"`
-		Path      string `json:"path"`
-		Left      any    `json:"leftValue,omitempty"`
-		Right     any    `json:"rightValue,omitempty"`
-		Key       string `json:"key,omitempty"`
-		Index     int    `json:"index,omitempty"`
-		FieldName string `json:"fieldName,omitempty"`
-		Change    string `json:"change"`
+		Type      string      `json:"type"`
+		Path      string      `json:"path"`
+		Left      any         `json:"leftValue,omitempty"`
+		Right     any         `json:"rightValue,omitempty"`
+		Key       string      `json:"key,omitempty"`
+		Index     int         `json:"index,omitempty"`
+		FromIndex *int        `json:"fromIndex,omitempty"`
+		ToIndex   *int        `json:"toIndex,omitempty"`
+		FieldName string      `json:"fieldName,omitempty"`
+		Change    string      `json:"change"`
+		Text      *TextDiff   `json:"text,omitempty"`
+		Edits     *EditScript `json:"edits,omitempty"`
 	}
 
 	changes := make([]jsonChange, 0, len(dr.Diffs))
 
 	for _, diff := range dr.Diffs {
-		var jc jsonChange
-		switch d := diff.(type) {
+		d := diff.base()
+		jc := jsonChange{
+			Type:   string(diff.Container()),
+			Path:   d.Path,
+			Left:   d.Left,
+			Right:  d.Right,
+			Change: string(diff.Kind()),
+			Text:   d.Text,
+			Edits:  d.Edits,
+		}
+		switch c := diff.(type) {
 		case *MapDiff:
-			jc = jsonChange{
-				Type:   "map",
-				Path:   d.Path,
-				Left:   d.Left,
-				Right:  d.Right,
-				Key:    fmt.Sprintf("%v", d.Key),
-				Change: string(d.ChangeType),
-			}
+			jc.Key = fmt.Sprintf("%v", c.Key)
 		case *SliceDiff:
-			jc = jsonChange{
-				Type:   "slice",
-				Path:   d.Path,
-				Left:   d.Left,
-				Right:  d.Right,
-				Index:  d.Index,
-				Change: string(d.ChangeType),
+			jc.Index = c.Index
+			if c.ChangeType == ChangeTypeMoved {
+				jc.FromIndex, jc.ToIndex = &c.FromIndex, &c.ToIndex
 			}
 		case *StructDiff:
-			parentPath := d.Path
-			if d.FieldName != "" {
-				pathParts := strings.Split(d.Path, ".")
-				if len(pathParts) > 1 && pathParts[len(pathParts)-1] == d.FieldName {
-					parentPath = strings.Join(pathParts[:len(pathParts)-1], ".")
-				} else if d.Path == d.FieldName {
-					parentPath = ""
+			jc.FieldName = c.FieldName
+			if c.FieldName != "" {
+				if parent, last := valueSteps(d).Parent(); last.Kind == StepField && last.fieldName() == c.FieldName {
+					jc.Path = parent.String()
 				}
 			}
-			jc = jsonChange{
-				Type:      "struct",
-				Path:      parentPath,
-				Left:      d.Left,
-				Right:     d.Right,
-				FieldName: d.FieldName,
-				Change:    string(d.ChangeType),
-			}
-		case *Diff:
-			jc = jsonChange{Type: "value", Path: d.Path, Left: d.Left, Right: d.Right, Change: "UPDATED"}
-		default:
-			jc = jsonChange{
-				Type:   "unknown",
-				Path:   "unknown",
-				Left:   nil,
-				Right:  nil,
-				Change: "UNKNOWN",
-			}
 		}
 		changes = append(changes, jc)
 	}
@@ -210,6 +192,10 @@ func (ct ChangeType) String() string {
 		return "removed"
 	case ChangeTypeUpdated:
 		return "updated"
+	case ChangeTypeTypeChanged:
+		return "type changed"
+	case ChangeTypeMoved:
+		return "moved"
 	default:
 		return string(ct)
 	}
@@ -166,13 +166,9 @@ func TestCircularReferences(t *testing.T) {
 			t.Errorf("Expected 1 diff, got %d", len(result.Diffs))
 		}
 		if len(result.Diffs) > 0 {
-			diff, ok := result.Diffs[0].(StructDiff)
+			diff, ok := result.Diffs[0].(*StructDiff)
 			if !ok {
-				if diffPtr, ok := result.Diffs[0].(*StructDiff); ok {
-					diff = *diffPtr
-				} else {
-					t.Fatalf("Expected StructDiff type, got %T", result.Diffs[0])
-				}
+				t.Fatalf("Expected StructDiff type, got %T", result.Diffs[0])
 			}
 			if diff.Path != "Next.Name" {
 				t.Errorf("Expected path 'Next.Name', got '%s'", diff.Path)
@@ -371,14 +367,9 @@ func TestInvalidDiffTags(t *testing.T) {
 		Field4: "value4_changed",
 	}
 
-	result, err := Compare(left, right)
-	if err != nil {
-		t.Fatalf("Compare failed: %v", err)
-	}
-
-	expectedDiffs := 3
-	if len(result.Diffs) != expectedDiffs {
-		t.Errorf("Expected %d differences, got %d: %s", expectedDiffs, len(result.Diffs), result.String())
+	_, err := Compare(left, right)
+	if err == nil || !strings.Contains(err.Error(), `unknown directive "invalid"`) {
+		t.Errorf("Expected error for unknown directive, got %v", err)
 	}
 }
 
@@ -502,27 +493,17 @@ func TestStringOutputAdditionalEdgeCases(t *testing.T) {
 		}
 	})
 
-	t.Run("unknown diff type", func(t *testing.T) {
-		result := &DiffResult{
-			Diffs: []any{"unknown type"},
-		}
-		output := result.String()
-		if !strings.Contains(output, "Unknown diff type") {
-			t.Errorf("Expected 'Unknown diff type' in output, got '%s'", output)
-		}
-	})
-
 	t.Run("struct diff with empty field name", func(t *testing.T) {
 		result := &DiffResult{
-			Diffs: []any{
+			Diffs: []Change{
 				&StructDiff{
 					Diff: Diff{
-						Path:  "test",
-						Left:  "left",
-						Right: "right",
+						Path:       "test",
+						Left:       "left",
+						Right:      "right",
+						ChangeType: ChangeTypeUpdated,
 					},
-					FieldName:  "",
-					ChangeType: ChangeTypeUpdated,
+					FieldName: "",
 				},
 			},
 		}
@@ -534,15 +515,15 @@ func TestStringOutputAdditionalEdgeCases(t *testing.T) {
 
 	t.Run("map diff with complex key", func(t *testing.T) {
 		result := &DiffResult{
-			Diffs: []any{
+			Diffs: []Change{
 				&MapDiff{
 					Diff: Diff{
-						Path:  "test",
-						Left:  "left",
-						Right: "right",
+						Path:       "test",
+						Left:       "left",
+						Right:      "right",
+						ChangeType: ChangeTypeUpdated,
 					},
-					Key:        map[string]int{"complex": 1},
-					ChangeType: ChangeTypeUpdated,
+					Key: map[string]int{"complex": 1},
 				},
 			},
 		}
@@ -562,19 +543,9 @@ func TestToJSONEdgeCases(t *testing.T) {
 		}
 	})
 
-	t.Run("unknown diff type", func(t *testing.T) {
-		result := &DiffResult{
-			Diffs: []any{"unknown type"},
-		}
-		jsonOutput := result.ToJSON()
-		if !strings.Contains(jsonOutput, "unknown") {
-			t.Errorf("Expected 'unknown' in JSON output, got '%s'", jsonOutput)
-		}
-	})
-
 	t.Run("JSON marshaling error simulation", func(t *testing.T) {
 		result := &DiffResult{
-			Diffs: []any{
+			Diffs: []Change{
 				&Diff{
 					Path:  "test",
 					Left:  func() {},
@@ -588,3 +559,148 @@ func TestToJSONEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestTextDiff(t *testing.T) {
+	left := "a\nb\nc\nd\ne\nf\ng\nh\ni"
+	right := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj"
+
+	tests := []struct {
+		name     string
+		context  int
+		expected string
+	}{
+		{"separate hunks", 1, "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -9 +9,2 @@\n i\n+j\n"},
+		{"merged hunk", 4, "@@ -1,9 +1,10 @@\n a\n-b\n+B\n c\n d\n e\n f\n g\n h\n i\n+j\n"},
+		{"no context", 0, "@@ -2 +2 @@\n-b\n+B\n@@ -9,0 +10 @@\n+j\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTextDiff(left, right, tt.context).String(); got != tt.expected {
+				t.Errorf("Expected\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+	if got := newTextDiff("x", "y\nx", 0).Hunks[0]; got.LeftStart != 0 || got.LeftLines != 0 || got.RightStart != 1 {
+		t.Errorf("Expected an insertion before the first line, got %+v", got)
+	}
+
+	type template struct {
+		Subject string
+		Body    string `diff:"text"`
+		Footer  string
+	}
+	l := template{Subject: "Hi", Body: "Dear Ann,\nthanks\nBye", Footer: "x\ny"}
+	r := template{Subject: "Hello", Body: "Dear Bob,\nthanks\nBye", Footer: "x\nz"}
+
+	result, err := Compare(l, r)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 3 || result.Diffs[0].base().Text != nil || result.Diffs[1].base().Text == nil || result.Diffs[2].base().Text != nil {
+		t.Fatalf("Expected a text diff for the tagged field only, got %s", result.String())
+	}
+	expected := "UPDATED Body:\n  @@ -1,3 +1,3 @@\n  -Dear Ann,\n  +Dear Bob,\n   thanks\n   Bye\n"
+	if output := result.String(); !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain\n%s\ngot\n%s", expected, output)
+	}
+
+	result, err = Compare(l, r, WithTextDiff(0))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if text := result.Diffs[2].base().Text; text == nil || len(text.Hunks) != 1 || len(text.Hunks[0].Lines) != 2 {
+		t.Fatalf("Expected a text diff without context for Footer, got %+v", text)
+	}
+	var changes []struct {
+		Path string    `json:"path"`
+		Text *TextDiff `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(result.ToJSON()), &changes); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if changes[2].Text == nil || !reflect.DeepEqual(changes[2].Text, result.Diffs[2].base().Text) {
+		t.Errorf("Expected hunks in JSON output, got %+v", changes[2].Text)
+	}
+
+	target := l
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if target != r {
+		t.Errorf("Expected %+v after Apply, got %+v", r, target)
+	}
+}
+
+func TestEditScripts(t *testing.T) {
+	tests := []struct {
+		left, right string
+		rendered    string
+		distance    int
+	}{
+		{"John", "Joan", "Jo[-h-]{+a+}n", 1},
+		{"kitten", "sitting", "[-k-]{+s+}itt[-e-]{+i+}n{+g+}", 3},
+		{"héllo", "hallo", "h[-é-]{+a+}llo", 1},
+		{"", "abc", "{+abc+}", 3},
+		{"ab-12", "ab-", "ab-[-12-]", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.left+"->"+tt.right, func(t *testing.T) {
+			script := newEditScript(tt.left, tt.right)
+			if got := script.String(); got != tt.rendered {
+				t.Errorf("Expected %s, got %s", tt.rendered, got)
+			}
+			if script.Distance != tt.distance {
+				t.Errorf("Expected distance %d, got %d", tt.distance, script.Distance)
+			}
+		})
+	}
+
+	type person struct {
+		Name string
+		Bio  string
+		Age  int
+	}
+	left := person{Name: "John", Bio: "Likes long walks on the beach", Age: 30}
+	right := person{Name: "Joan", Bio: "Likes short walks in the park", Age: 31}
+
+	result, err := Compare(left, right, WithEditScripts(10))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if len(result.Diffs) != 3 || result.Diffs[0].base().Edits == nil || result.Diffs[1].base().Edits != nil || result.Diffs[2].base().Edits != nil {
+		t.Fatalf("Expected an edit script for the short string only, got %s", result.String())
+	}
+	if output := result.String(); !strings.Contains(output, "UPDATED Name: Jo[-h-]{+a+}n\n") {
+		t.Errorf("Expected highlighted edit in output, got %s", output)
+	}
+	if output := result.ToJSON(); !strings.Contains(output, `"distance": 1`) {
+		t.Errorf("Expected edit distance in JSON output, got %s", output)
+	}
+
+	result, err = Compare(left, right, WithEditScripts(0))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if edits := result.Diffs[1].base().Edits; edits == nil || edits.Distance == 0 {
+		t.Errorf("Expected an edit script for strings of any length, got %+v", edits)
+	}
+
+	type roster struct {
+		Names []string
+		Roles map[string]string
+	}
+	l := roster{Names: []string{"John", "Ann"}, Roles: map[string]string{"John": "lead"}}
+	r := roster{Names: []string{"Joan", "Ann"}, Roles: map[string]string{"John": "load"}}
+	for _, opt := range []CompareOption{WithEditScripts(10), WithSliceAlgorithm(SliceLCS)} {
+		result, err := Compare(l, r, WithEditScripts(10), opt)
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		output := result.String()
+		for _, expected := range []string{"UPDATED Names[0]: Jo[-h-]{+a+}n\n", "UPDATED Roles[John]: l[-e-]{+o+}ad\n"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected output to contain %q, got %s", expected, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package godiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PathStepKind identifies how a PathStep descends into a value
+type PathStepKind int
+
+const (
+	StepField     PathStepKind = iota // struct field access
+	StepIndex                         // slice or array element access
+	StepMapKey                        // map entry access
+	StepPointer                       // pointer dereference
+	StepInterface                     // interface unwrapping
+	StepIdentity                      // slice element access by identity key
+)
+
+// PathStep is a single step of a Path
+type PathStep struct {
+	Kind  PathStepKind
+	Name  string // Field name for StepField, key name for StepIdentity
+	Label string // Name rendered for StepField if it differs from the field name, see PathNaming
+	Index int    // Element index for StepIndex and StepIdentity (-1 if unknown)
+	Key   any    // Map key for StepMapKey, identity key for StepIdentity
+}
+
+// Path is the typed location of a value below the compared root
+type Path []PathStep
+
+// with returns a copy of p extended by step. The copy keeps paths recorded on
+// diffs independent of the paths still being extended during the comparison.
+func (p Path) with(step PathStep) Path {
+	np := make(Path, len(p), len(p)+1)
+	copy(np, p)
+	return append(np, step)
+}
+
+// field returns a copy of p extended by a struct field step
+func (p Path) field(name string) Path {
+	return p.with(PathStep{Kind: StepField, Name: name})
+}
+
+// index returns a copy of p extended by a slice index step
+func (p Path) index(i int) Path {
+	return p.with(PathStep{Kind: StepIndex, Index: i})
+}
+
+// key returns a copy of p extended by a map key step
+func (p Path) key(k any) Path {
+	return p.with(PathStep{Kind: StepMapKey, Key: k})
+}
+
+// fieldName returns the name a field step is rendered with
+func (s PathStep) fieldName() string {
+	if s.Label != "" {
+		return s.Label
+	}
+	return s.Name
+}
+
+// identity returns a copy of p extended by a step addressing the slice element
+// whose key named name equals key, found at index
+func (p Path) identity(name string, key any, index int) Path {
+	return p.with(PathStep{Kind: StepIdentity, Name: name, Key: key, Index: index})
+}
+
+// Parent returns the path without its last field, index, map key or identity step, along
+// with that step. Trailing pointer and interface steps are dropped as well.
+func (p Path) Parent() (Path, PathStep) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].Kind != StepPointer && p[i].Kind != StepInterface {
+			return p[:i], p[i]
+		}
+	}
+	return nil, PathStep{}
+}
+
+// String renders the path in the dotted form used by Diff.Path, e.g. `Users[2].Tags[admin]`
+// or `Orders[ID=42].Status`. Pointer and interface steps are not rendered. Map and identity
+// keys containing `]` or `"`, and map keys that would read as identity steps, are quoted.
+func (p Path) String() string {
+	var sb strings.Builder
+	for _, step := range p {
+		switch step.Kind {
+		case StepField:
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(step.fieldName())
+		case StepIndex:
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(step.Index))
+			sb.WriteByte(']')
+		case StepMapKey, StepIdentity:
+			keyStr := fmt.Sprintf("%v", step.Key)
+			sb.WriteByte('[')
+			if step.Kind == StepIdentity {
+				sb.WriteString(step.Name)
+				sb.WriteByte('=')
+			}
+			if strings.ContainsAny(keyStr, `]"`) || (step.Kind == StepMapKey && identityName(keyStr) != "") {
+				sb.WriteString(strconv.Quote(keyStr))
+			} else {
+				sb.WriteString(keyStr)
+			}
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// JSONPointer renders the path as an RFC 6901 JSON Pointer using the same field labels
+// as String, which follow WithPathNaming and diff name tags. Identity steps are rendered as the element index they were found at.
+func (p Path) JSONPointer() string {
+	var sb strings.Builder
+	for _, step := range p {
+		switch step.Kind {
+		case StepField:
+			sb.WriteByte('/')
+			sb.WriteString(escapeJSONPointerToken(step.fieldName()))
+		case StepIndex, StepIdentity:
+			sb.WriteByte('/')
+			sb.WriteString(strconv.Itoa(step.Index))
+		case StepMapKey:
+			sb.WriteByte('/')
+			sb.WriteString(escapeJSONPointerToken(fmt.Sprintf("%v", step.Key)))
+		}
+	}
+	return sb.String()
+}
+
+// JSONPath renders the path as a JSONPath expression, e.g. `$.Users[2].Tags['admin']`.
+// Identity steps become filter expressions such as `$.Orders[?(@.ID==42)]`.
+func (p Path) JSONPath() string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, step := range p {
+		switch step.Kind {
+		case StepField:
+			sb.WriteByte('.')
+			sb.WriteString(step.fieldName())
+		case StepIndex:
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(step.Index))
+			sb.WriteByte(']')
+		case StepMapKey:
+			sb.WriteByte('[')
+			writeJSONPathLiteral(&sb, step.Key)
+			sb.WriteByte(']')
+		case StepIdentity:
+			sb.WriteString("[?(@.")
+			sb.WriteString(step.Name)
+			sb.WriteString("==")
+			writeJSONPathLiteral(&sb, step.Key)
+			sb.WriteString(")]")
+		}
+	}
+	return sb.String()
+}
+
+// writeJSONPathLiteral writes a key as a JSONPath number or single-quoted string
+func writeJSONPathLiteral(sb *strings.Builder, key any) {
+	if _, isString := key.(string); !isString && isNumericKey(key) {
+		fmt.Fprintf(sb, "%v", key)
+		return
+	}
+	keyStr := fmt.Sprintf("%v", key)
+	keyStr = strings.ReplaceAll(keyStr, `\`, `\\`)
+	keyStr = strings.ReplaceAll(keyStr, `'`, `\'`)
+	sb.WriteByte('\'')
+	sb.WriteString(keyStr)
+	sb.WriteByte('\'')
+}
+
+// isNumericKey reports whether a map key is an integer or floating-point number
+func isNumericKey(key any) bool {
+	switch key.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64:
+		return true
+	}
+	return false
+}
+
+// ParsePath parses the dotted form produced by Path.String. Bracketed non-negative
+// integers become StepIndex steps, bracketed `name=value` pairs with an identifier
+// name StepIdentity steps with a string key, and all other bracketed values StepMapKey
+// steps with a string key; when a path is resolved against a value, index steps also
+// match map keys and map key and identity steps match keys by their formatted form.
+func ParsePath(s string) (Path, error) {
+	var path Path
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			if i == 0 || i+1 >= len(s) || s[i+1] == '.' || s[i+1] == '[' {
+				return nil, fmt.Errorf("invalid path %q: empty field name at offset %d", s, i)
+			}
+			i++
+		case '[':
+			name := identityName(s[i+1:])
+			keyStart := i + 1
+			if name != "" {
+				keyStart += len(name) + 1
+			}
+			if keyStart < len(s) && s[keyStart] == '"' {
+				quoted, err := strconv.QuotedPrefix(s[keyStart:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: bad quoted key at offset %d", s, i)
+				}
+				end := keyStart + len(quoted)
+				if end >= len(s) || s[end] != ']' {
+					return nil, fmt.Errorf("invalid path %q: unterminated bracket at offset %d", s, i)
+				}
+				key, _ := strconv.Unquote(quoted)
+				if name != "" {
+					path = append(path, PathStep{Kind: StepIdentity, Name: name, Key: key, Index: -1})
+				} else {
+					path = append(path, PathStep{Kind: StepMapKey, Key: key})
+				}
+				i = end + 1
+				continue
+			}
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unterminated bracket at offset %d", s, i)
+			}
+			content := s[i+1 : i+end]
+			if index, err := strconv.Atoi(content); err == nil && index >= 0 && !strings.HasPrefix(content, "+") {
+				path = append(path, PathStep{Kind: StepIndex, Index: index})
+			} else if name != "" && keyStart <= i+end {
+				path = append(path, PathStep{Kind: StepIdentity, Name: name, Key: s[keyStart : i+end], Index: -1})
+			} else {
+				path = append(path, PathStep{Kind: StepMapKey, Key: content})
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(s[i:], ".[")
+			if end < 0 {
+				end = len(s) - i
+			}
+			path = append(path, PathStep{Kind: StepField, Name: s[i : i+end]})
+			i += end
+		}
+	}
+	return path, nil
+}
+
+// identityName returns the identifier before a `=` at the start of s, or "" if s does
+// not start with `identifier=`
+func identityName(s string) string {
+	for i, r := range s {
+		switch {
+		case r == '=' && i > 0:
+			return s[:i]
+		case r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// parsedSteps parses a dotted path, returning nil if it is malformed
+func parsedSteps(s string) Path {
+	path, err := ParsePath(s)
+	if err != nil {
+		return nil
+	}
+	return path
+}
@@ -0,0 +1,238 @@
+package godiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathRendering(t *testing.T) {
+	path := Path{
+		{Kind: StepField, Name: "Users"},
+		{Kind: StepIndex, Index: 2},
+		{Kind: StepPointer},
+		{Kind: StepField, Name: "Tags"},
+		{Kind: StepMapKey, Key: "a.b]c"},
+		{Kind: StepMapKey, Key: 7},
+		{Kind: StepMapKey, Key: "it's/~"},
+	}
+
+	if got, want := path.String(), `Users[2].Tags["a.b]c"][7][it's/~]`; got != want {
+		t.Errorf("String: expected %s, got %s", want, got)
+	}
+	if got, want := path.JSONPointer(), `/Users/2/Tags/a.b]c/7/it's~1~0`; got != want {
+		t.Errorf("JSONPointer: expected %s, got %s", want, got)
+	}
+	if got, want := path.JSONPath(), `$.Users[2].Tags['a.b]c'][7]['it\'s/~']`; got != want {
+		t.Errorf("JSONPath: expected %s, got %s", want, got)
+	}
+	identity := Path{{Kind: StepField, Name: "Orders"}, {Kind: StepIdentity, Name: "ID", Key: 42, Index: 3}, {Kind: StepField, Name: "Status"}}
+	if got, want := identity.String(), "Orders[ID=42].Status"; got != want {
+		t.Errorf("String: expected %s, got %s", want, got)
+	}
+	if got, want := identity.JSONPointer(), "/Orders/3/Status"; got != want {
+		t.Errorf("JSONPointer: expected %s, got %s", want, got)
+	}
+	if got, want := identity.JSONPath(), "$.Orders[?(@.ID==42)].Status"; got != want {
+		t.Errorf("JSONPath: expected %s, got %s", want, got)
+	}
+	if got := (Path{}).String(); got != "" {
+		t.Errorf("Expected empty root path, got %q", got)
+	}
+	if got := (Path{{Kind: StepIndex, Index: 0}, {Kind: StepField, Name: "Name"}}).String(); got != "[0].Name" {
+		t.Errorf("Expected [0].Name, got %q", got)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Path
+	}{
+		{"", nil},
+		{"Name", Path{{Kind: StepField, Name: "Name"}}},
+		{"A.B[3]", Path{{Kind: StepField, Name: "A"}, {Kind: StepField, Name: "B"}, {Kind: StepIndex, Index: 3}}},
+		{"[k][-1]", Path{{Kind: StepMapKey, Key: "k"}, {Kind: StepMapKey, Key: "-1"}}},
+		{`M["a.b]c"].X`, Path{{Kind: StepField, Name: "M"}, {Kind: StepMapKey, Key: "a.b]c"}, {Kind: StepField, Name: "X"}}},
+		{"M[a.b]", Path{{Kind: StepField, Name: "M"}, {Kind: StepMapKey, Key: "a.b"}}},
+		{"Orders[ID=42].Status", Path{{Kind: StepField, Name: "Orders"}, {Kind: StepIdentity, Name: "ID", Key: "42", Index: -1}, {Kind: StepField, Name: "Status"}}},
+		{`Orders[code="a]b"]`, Path{{Kind: StepField, Name: "Orders"}, {Kind: StepIdentity, Name: "code", Key: "a]b", Index: -1}}},
+		{`M["a=b"]`, Path{{Kind: StepField, Name: "M"}, {Kind: StepMapKey, Key: "a=b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePath(tt.input)
+			if err != nil {
+				t.Fatalf("ParsePath failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %#v, got %#v", tt.expected, got)
+			}
+			if got.String() != tt.input {
+				t.Errorf("Expected round trip to %q, got %q", tt.input, got.String())
+			}
+		})
+	}
+
+	for _, invalid := range []string{"A[1", ".A", "A..B", "A.", `M["x`, `M["x"`} {
+		if _, err := ParsePath(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestPathParent(t *testing.T) {
+	path := Path{{Kind: StepField, Name: "A"}, {Kind: StepIndex, Index: 1}, {Kind: StepPointer}}
+	parent, last := path.Parent()
+	if parent.String() != "A" || last.Kind != StepIndex || last.Index != 1 {
+		t.Errorf("Unexpected parent %q and step %#v", parent, last)
+	}
+	if parent, last := (Path{}).Parent(); parent != nil || last != (PathStep{}) {
+		t.Errorf("Expected empty parent for root, got %q", parent)
+	}
+}
+
+func TestDiffSteps(t *testing.T) {
+	type inner struct {
+		Value int
+	}
+	type outer struct {
+		Items []inner
+		Ptr   *inner
+		Meta  map[string]inner
+		Tags  []string
+	}
+
+	left := outer{
+		Items: []inner{{Value: 1}},
+		Ptr:   &inner{Value: 1},
+		Meta:  map[string]inner{"a.b]": {Value: 1}},
+		Tags:  []string{"x"},
+	}
+	right := outer{
+		Items: []inner{{Value: 2}},
+		Ptr:   &inner{Value: 2},
+		Meta:  map[string]inner{"a.b]": {Value: 2}},
+		Tags:  []string{"y"},
+	}
+
+	result, err := Compare(left, right)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"Items[0].Value":     "/Items/0/Value",
+		"Ptr.Value":          "/Ptr/Value",
+		`Meta["a.b]"].Value`: "/Meta/a.b]/Value",
+		"Tags":               "/Tags/0",
+	}
+	if len(result.Diffs) != len(expected) {
+		t.Fatalf("Expected %d diffs, got %d: %s", len(expected), len(result.Diffs), result.String())
+	}
+	for _, diff := range result.Diffs {
+		d := diff.base()
+		pointer, ok := expected[d.Path]
+		if !ok {
+			t.Errorf("Unexpected diff path %q", d.Path)
+			continue
+		}
+		if d.Steps.JSONPointer() != pointer {
+			t.Errorf("Expected steps %s for %q, got %s", pointer, d.Path, d.Steps.JSONPointer())
+		}
+		if lookupPath(reflect.ValueOf(right), d.Steps).Interface() != d.Right {
+			t.Errorf("Steps of %q do not resolve to the right value %v", d.Path, d.Right)
+		}
+	}
+
+	target := left
+	target.Items = []inner{{Value: 1}}
+	target.Ptr = &inner{Value: 1}
+	target.Meta = map[string]inner{"a.b]": {Value: 1}}
+	target.Tags = []string{"x"}
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !reflect.DeepEqual(target, right) {
+		t.Errorf("Expected %+v after Apply, got %+v", right, target)
+	}
+}
+
+func TestAddDiffHelpersParseSteps(t *testing.T) {
+	result := &DiffResult{}
+	result.AddSliceDiff("Items", 3, nil, 1, ChangeTypeAdded)
+	result.AddMapDiff("Meta[k]", "k", 1, 2, ChangeTypeUpdated)
+
+	if got := result.Diffs[0].(*SliceDiff).Steps.String(); got != "Items[3]" {
+		t.Errorf("Expected slice diff steps Items[3], got %s", got)
+	}
+	if got := result.Diffs[1].(*MapDiff).Steps.JSONPointer(); got != "/Meta/k" {
+		t.Errorf("Expected map diff steps /Meta/k, got %s", got)
+	}
+}
+
+func TestPathNaming(t *testing.T) {
+	type Contact struct {
+		EmailAddress string `json:"email" yaml:"mail"`
+	}
+	type Customer struct {
+		PatchBase
+		FullName string    `json:"full_name,omitempty" yaml:"fullName"`
+		Contacts []Contact `json:"contacts"`
+		Internal string    `json:"-" yaml:"-"`
+		Plain    string
+	}
+
+	left := Customer{PatchBase: PatchBase{ID: 1}, FullName: "Ann", Contacts: []Contact{{EmailAddress: "a@x"}}, Internal: "a", Plain: "p"}
+	right := Customer{PatchBase: PatchBase{ID: 2}, FullName: "Bob", Contacts: []Contact{{EmailAddress: "b@x"}}, Internal: "b", Plain: "q"}
+
+	paths := func(result *DiffResult) []string {
+		var paths []string
+		for _, diff := range result.Diffs {
+			paths = append(paths, diff.base().Path)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name     string
+		opts     []CompareOption
+		expected []string
+	}{
+		{"go", []CompareOption{WithPathNaming(NamingGo)}, []string{"PatchBase.ID", "FullName", "Contacts[0].EmailAddress", "Internal", "Plain"}},
+		{"json", []CompareOption{WithPathNaming(NamingJSON)}, []string{"PatchBase.id", "full_name", "contacts[0].email", "Internal", "Plain"}},
+		{"json skipping omitted", []CompareOption{WithPathNaming(NamingJSON), WithSkipOmittedFields()}, []string{"PatchBase.id", "full_name", "contacts[0].email", "Plain"}},
+		{"yaml", []CompareOption{WithPathNaming(NamingYAML)}, []string{"patchbase.id", "fullName", "contacts[0].mail", "Internal", "plain"}},
+		{"json ignore patterns", []CompareOption{WithPathNaming(NamingJSON), WithIgnoreFields("contacts[*].email", "full_name", "id")}, []string{"Internal", "Plain"}},
+		{"custom", []CompareOption{WithPathNaming(func(field reflect.StructField) (string, bool) {
+			return strings.ToUpper(field.Name), field.Name == "Plain"
+		}), WithSkipOmittedFields()}, []string{"PATCHBASE.ID", "FULLNAME", "CONTACTS[0].EMAILADDRESS", "INTERNAL"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(left, right, tt.opts...)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if got := paths(result); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	result, err := Compare(left, right, WithPathNaming(NamingJSON))
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if fieldName := result.Diffs[1].(*StructDiff).FieldName; fieldName != "full_name" {
+		t.Errorf("Expected field name full_name, got %s", fieldName)
+	}
+	target := left
+	target.Contacts = []Contact{{EmailAddress: "a@x"}}
+	if err := result.Apply(&target); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !reflect.DeepEqual(target, right) {
+		t.Errorf("Expected %+v after Apply, got %+v", right, target)
+	}
+}
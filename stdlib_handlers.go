@@ -0,0 +1,237 @@
+package godiff
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// BigNumberHandler handles *big.Int, *big.Float and *big.Rat comparisons by numeric value
+type BigNumberHandler struct{}
+
+func (h *BigNumberHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[*big.Int]() || typ == reflect.TypeFor[*big.Float]() || typ == reflect.TypeFor[*big.Rat]()
+}
+
+func (h *BigNumberHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	var equal bool
+	switch l := left.(type) {
+	case *big.Int:
+		r, ok := right.(*big.Int)
+		if !ok {
+			return fmt.Errorf("BigNumberHandler received mismatched values: left=%T, right=%T", left, right)
+		}
+		equal = l == r || l != nil && r != nil && l.Cmp(r) == 0
+	case *big.Float:
+		r, ok := right.(*big.Float)
+		if !ok {
+			return fmt.Errorf("BigNumberHandler received mismatched values: left=%T, right=%T", left, right)
+		}
+		equal = l == r || l != nil && r != nil && l.Cmp(r) == 0
+	case *big.Rat:
+		r, ok := right.(*big.Rat)
+		if !ok {
+			return fmt.Errorf("BigNumberHandler received mismatched values: left=%T, right=%T", left, right)
+		}
+		equal = l == r || l != nil && r != nil && l.Cmp(r) == 0
+	default:
+		return fmt.Errorf("BigNumberHandler received non-big values: left=%T, right=%T", left, right)
+	}
+	if !equal {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
+
+// NetipHandler handles netip.Addr, netip.AddrPort and netip.Prefix comparisons
+type NetipHandler struct{}
+
+func (h *NetipHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[netip.Addr]() || typ == reflect.TypeFor[netip.AddrPort]() || typ == reflect.TypeFor[netip.Prefix]()
+}
+
+func (h *NetipHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	if left != right {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: ChangeTypeUpdated})
+	}
+	return nil
+}
+
+// IPHandler handles net.IP comparisons, treating an IPv4 address and its IPv4-in-IPv6 form as equal
+type IPHandler struct{}
+
+func (h *IPHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[net.IP]()
+}
+
+func (h *IPHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	leftIP, ok1 := left.(net.IP)
+	rightIP, ok2 := right.(net.IP)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("IPHandler received non-IP values: left=%T, right=%T", left, right)
+	}
+	if !leftIP.Equal(rightIP) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: leftIP, Right: rightIP, ChangeType: changeTypeFor(leftIP, rightIP)})
+	}
+	return nil
+}
+
+// URLHandler handles *url.URL and url.URL comparisons by their string form
+type URLHandler struct{}
+
+func (h *URLHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[*url.URL]() || typ == reflect.TypeFor[url.URL]()
+}
+
+func (h *URLHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	if !stringersEqual(left, right) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
+
+// RegexpHandler handles *regexp.Regexp comparisons by their source expression
+type RegexpHandler struct{}
+
+func (h *RegexpHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[*regexp.Regexp]()
+}
+
+func (h *RegexpHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	if !stringersEqual(left, right) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
+
+// LocationHandler handles *time.Location and time.Location comparisons by name
+type LocationHandler struct{}
+
+func (h *LocationHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[*time.Location]() || typ == reflect.TypeFor[time.Location]()
+}
+
+func (h *LocationHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	if loc, ok := left.(time.Location); ok {
+		left = &loc
+	}
+	if loc, ok := right.(time.Location); ok {
+		right = &loc
+	}
+	if !stringersEqual(left, right) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
+
+// stringersEqual compares two values of the same pointer or value type by their String
+// method. Nil pointers are only equal to each other.
+func stringersEqual(left, right any) bool {
+	if isNilValue(left) || isNilValue(right) {
+		return isNilValue(left) && isNilValue(right)
+	}
+	leftStringer, ok1 := left.(fmt.Stringer)
+	rightStringer, ok2 := right.(fmt.Stringer)
+	if !ok1 || !ok2 {
+		return reflect.DeepEqual(left, right)
+	}
+	return leftStringer.String() == rightStringer.String()
+}
+
+// RawJSONHandler handles json.RawMessage comparisons semantically. Objects and arrays are
+// decoded and diffed member by member, so that documents that only differ in formatting or
+// member order are equal; other documents are compared as a whole.
+type RawJSONHandler struct{}
+
+func (h *RawJSONHandler) CanHandle(typ reflect.Type) bool {
+	return typ == reflect.TypeFor[json.RawMessage]()
+}
+
+func (h *RawJSONHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	leftRaw, ok1 := left.(json.RawMessage)
+	rightRaw, ok2 := right.(json.RawMessage)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("RawJSONHandler received non-JSON values: left=%T, right=%T", left, right)
+	}
+	if bytes.Equal(leftRaw, rightRaw) {
+		return nil
+	}
+	if compared, err := compareJSONDocuments(config.typedPath(path), leftRaw, rightRaw, result, config); compared || err != nil {
+		return err
+	}
+	result.Diffs = append(result.Diffs, &Diff{Path: path, Left: leftRaw, Right: rightRaw, ChangeType: changeTypeFor(leftRaw, rightRaw)})
+	return nil
+}
+
+// SQLNullHandler handles the sql.Null types, such as sql.NullString or sql.Null[T]. Values
+// that are not Valid are equal regardless of their content; a change from an invalid to a
+// valid value is reported as ADDED, the reverse as REMOVED.
+type SQLNullHandler struct{}
+
+func (h *SQLNullHandler) CanHandle(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ.PkgPath() != reflect.TypeFor[sql.NullString]().PkgPath() || typ.NumField() != 2 {
+		return false
+	}
+	valid, ok := typ.FieldByName("Valid")
+	return ok && valid.Type.Kind() == reflect.Bool
+}
+
+func (h *SQLNullHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	leftVal := reflect.ValueOf(left)
+	rightVal := reflect.ValueOf(right)
+	if !leftVal.IsValid() || !rightVal.IsValid() || leftVal.Type() != rightVal.Type() || !h.CanHandle(leftVal.Type()) {
+		return fmt.Errorf("SQLNullHandler received mismatched values: left=%T, right=%T", left, right)
+	}
+
+	leftValid := leftVal.FieldByName("Valid").Bool()
+	rightValid := rightVal.FieldByName("Valid").Bool()
+	var changeType ChangeType
+	switch {
+	case !leftValid && !rightValid:
+		return nil
+	case !leftValid:
+		changeType = ChangeTypeAdded
+	case !rightValid:
+		changeType = ChangeTypeRemoved
+	default:
+		valueField := 0
+		if leftVal.Type().Field(0).Name == "Valid" {
+			valueField = 1
+		}
+		equal, err := valuesEqual(config.typedPath(path), leftVal.Field(valueField).Interface(), rightVal.Field(valueField).Interface(), config)
+		if err != nil || equal {
+			return err
+		}
+		changeType = ChangeTypeUpdated
+	}
+	result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeType})
+	return nil
+}
+
+// BytesHandler handles byte slices as a whole instead of element by element. Byte slices
+// are rendered as hex by DiffResult.String and as base64 by DiffResult.ToJSON.
+type BytesHandler struct{}
+
+func (h *BytesHandler) CanHandle(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+func (h *BytesHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	leftVal := reflect.ValueOf(left)
+	rightVal := reflect.ValueOf(right)
+	if !leftVal.IsValid() || !rightVal.IsValid() || !h.CanHandle(leftVal.Type()) || leftVal.Type() != rightVal.Type() {
+		return fmt.Errorf("BytesHandler received non-byte values: left=%T, right=%T", left, right)
+	}
+	if !bytes.Equal(leftVal.Bytes(), rightVal.Bytes()) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package godiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fieldTag is the parsed `diff` struct tag of a field
+type fieldTag struct {
+	ignore           bool
+	ignoreOrder      bool
+	name             string // name= renders the field under this name in paths
+	key              string // key= matches slice elements by this field
+	floatTolerance   float64
+	timeTolerance    time.Duration
+	timeTruncate     time.Duration
+	ignoreCase       bool
+	ignoreWhitespace bool
+	redact           bool
+	comparator       string // compare= names a comparator registered with WithNamedComparator
+	deep             bool
+	shallow          bool
+	omitEmptyEqual   bool
+	json             bool // json compares string and json.RawMessage fields as JSON documents
+	text             bool
+}
+
+// adjustsConfig reports whether the tag changes the configuration the field is compared with
+func (t *fieldTag) adjustsConfig() bool {
+	return t.ignoreOrder || t.floatTolerance > 0 || t.timeTolerance > 0 || t.timeTruncate > 0 ||
+		t.ignoreCase || t.ignoreWhitespace || t.deep || t.text
+}
+
+// structTags holds the parsed diff tags of a struct type, indexed like its fields
+type structTags struct {
+	fields []fieldTag
+	err    error
+}
+
+// structTagCache caches the parsed diff tags per struct type
+var structTagCache sync.Map // reflect.Type -> *structTags
+
+// tagsFor returns the parsed diff tags of the fields of a struct type
+func tagsFor(typ reflect.Type) ([]fieldTag, error) {
+	if cached, ok := structTagCache.Load(typ); ok {
+		tags := cached.(*structTags)
+		return tags.fields, tags.err
+	}
+	tags := &structTags{fields: make([]fieldTag, typ.NumField())}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag, err := parseDiffTag(field.Tag.Get("diff"))
+		if err != nil {
+			tags.fields, tags.err = nil, fmt.Errorf("invalid diff tag on %s.%s: %w", typ, field.Name, err)
+			break
+		}
+		if tag.json && field.Type.Kind() != reflect.String && field.Type != reflect.TypeFor[json.RawMessage]() {
+			tags.fields, tags.err = nil, fmt.Errorf("invalid diff tag on %s.%s: directive \"json\" needs a string or json.RawMessage field", typ, field.Name)
+			break
+		}
+		tags.fields[i] = tag
+	}
+	cached, _ := structTagCache.LoadOrStore(typ, tags)
+	tags = cached.(*structTags)
+	return tags.fields, tags.err
+}
+
+// parseDiffTag parses a comma separated list of diff tag directives
+func parseDiffTag(diffTag string) (fieldTag, error) {
+	var tag fieldTag
+	if diffTag == "" {
+		return tag, nil
+	}
+	for directive := range strings.SplitSeq(diffTag, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(directive), "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if hasValue && value == "" {
+			return tag, fmt.Errorf("directive %q needs a value", name)
+		}
+
+		var flag *bool
+		switch name {
+		case "":
+			continue
+		case "ignore":
+			flag = &tag.ignore
+		case "ignoreOrder":
+			flag = &tag.ignoreOrder
+		case "ignoreCase":
+			flag = &tag.ignoreCase
+		case "ignoreWhitespace":
+			flag = &tag.ignoreWhitespace
+		case "redact":
+			flag = &tag.redact
+		case "deep":
+			flag = &tag.deep
+		case "shallow":
+			flag = &tag.shallow
+		case "omitEmptyEqual":
+			flag = &tag.omitEmptyEqual
+		case "json":
+			flag = &tag.json
+		case "text":
+			flag = &tag.text
+		case "name", "key", "compare":
+			if !hasValue {
+				return tag, fmt.Errorf("directive %q needs a value", name)
+			}
+			switch name {
+			case "name":
+				tag.name = value
+			case "key":
+				tag.key = value
+			default:
+				tag.comparator = value
+			}
+			continue
+		case "tolerance":
+			if !hasValue {
+				return tag, fmt.Errorf("directive %q needs a value", name)
+			}
+			if abs, err := strconv.ParseFloat(value, 64); err == nil {
+				tag.floatTolerance = abs
+			} else if d, err := time.ParseDuration(value); err == nil {
+				tag.timeTolerance = d
+			} else {
+				return tag, fmt.Errorf("tolerance %q is neither a number nor a duration", value)
+			}
+			continue
+		case "truncate":
+			if !hasValue {
+				return tag, fmt.Errorf("directive %q needs a value", name)
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return tag, fmt.Errorf("truncate %q is not a duration", value)
+			}
+			tag.timeTruncate = d
+			continue
+		default:
+			return tag, fmt.Errorf("unknown directive %q", name)
+		}
+		if hasValue {
+			return tag, fmt.Errorf("directive %q does not take a value", name)
+		}
+		*flag = true
+	}
+	if tag.deep && tag.shallow {
+		return tag, fmt.Errorf("directives \"deep\" and \"shallow\" are exclusive")
+	}
+	return tag, nil
+}
+
+// fieldConfig returns a copy of the config adjusted by the directives of a field's tag
+func (c *CompareConfig) fieldConfig(tag *fieldTag) *CompareConfig {
+	fieldConfig := *c
+	if tag.ignoreOrder {
+		fieldConfig.IgnoreSliceOrder = true
+	}
+	if tag.floatTolerance > 0 {
+		fieldConfig.FloatAbsTolerance = tag.floatTolerance
+	}
+	if tag.timeTolerance > 0 {
+		fieldConfig.TimeTolerance = tag.timeTolerance
+	}
+	if tag.timeTruncate > 0 {
+		fieldConfig.TimeTruncate = tag.timeTruncate
+	}
+	if tag.ignoreCase {
+		fieldConfig.ignoreCase = true
+	}
+	if tag.ignoreWhitespace {
+		fieldConfig.ignoreWhitespace = true
+	}
+	if tag.deep {
+		fieldConfig.MaxDepth = 0
+	}
+	if tag.text {
+		fieldConfig.TextDiffs = true
+	}
+	return &fieldConfig
+}
+
+// redacted is the type of Redacted
+type redacted struct{}
+
+// Redacted replaces both values of a change to a field tagged `diff:"redact"`.
+// Redacted changes cannot be applied.
+var Redacted any = redacted{}
+
+// String returns the placeholder printed for redacted values
+func (redacted) String() string {
+	return "[REDACTED]"
+}
+
+// MarshalJSON renders redacted values as a placeholder string
+func (redacted) MarshalJSON() ([]byte, error) {
+	return []byte(`"[REDACTED]"`), nil
+}
+
+// isEmptyValue reports whether v is nil, a zero-length slice, map, array or string, or a
+// pointer or interface holding such a value
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil() || isEmptyValue(v.Elem())
+	case reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package godiff
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextOp marks a line of a TextHunk as context, deleted or inserted
+type TextOp string
+
+const (
+	TextContext  TextOp = " "
+	TextDeleted  TextOp = "-"
+	TextInserted TextOp = "+"
+)
+
+// TextDiff is a line-based unified diff of a multi-line string change, see WithTextDiff
+type TextDiff struct {
+	Hunks []TextHunk `json:"hunks"`
+}
+
+// TextHunk is a run of changed lines with their surrounding context. Line numbers start
+// at 1; the start of an empty side is the line before which lines are inserted.
+type TextHunk struct {
+	LeftStart  int        `json:"leftStart"`
+	LeftLines  int        `json:"leftLines"`
+	RightStart int        `json:"rightStart"`
+	RightLines int        `json:"rightLines"`
+	Lines      []TextLine `json:"lines"`
+}
+
+// TextLine is a single line of a TextHunk
+type TextLine struct {
+	Op   TextOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// String renders the diff in unified format, without file headers
+func (t *TextDiff) String() string {
+	var sb strings.Builder
+	for _, hunk := range t.Hunks {
+		sb.WriteString("@@ -")
+		writeHunkRange(&sb, hunk.LeftStart, hunk.LeftLines)
+		sb.WriteString(" +")
+		writeHunkRange(&sb, hunk.RightStart, hunk.RightLines)
+		sb.WriteString(" @@\n")
+		for _, line := range hunk.Lines {
+			sb.WriteString(string(line.Op))
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// writeHunkRange writes a hunk range, omitting a count of one like diff -u does
+func writeHunkRange(sb *strings.Builder, start, lines int) {
+	sb.WriteString(strconv.Itoa(start))
+	if lines != 1 {
+		sb.WriteByte(',')
+		sb.WriteString(strconv.Itoa(lines))
+	}
+}
+
+// newTextDiff computes the line diff of two strings with the given number of context lines
+func newTextDiff(left, right string, context int) *TextDiff {
+	leftLines, rightLines := strings.Split(left, "\n"), strings.Split(right, "\n")
+	edits := shortestEdits(len(leftLines), len(rightLines), func(i, j int) bool {
+		return leftLines[i] == rightLines[j]
+	})
+	context = max(context, 0)
+
+	t := &TextDiff{}
+	for i := 0; i < len(edits); {
+		if edits[i].op == editMatch {
+			i++
+			continue
+		}
+		// extend the hunk over later changes separated by at most twice the context
+		start, end := max(i-context, 0), i
+		for end < len(edits) {
+			if edits[end].op != editMatch {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == editMatch {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = run
+		}
+		t.Hunks = append(t.Hunks, textHunk(edits[start:end], leftLines, rightLines))
+		i = end
+	}
+	return t
+}
+
+// textHunk builds the hunk covering a run of edits
+func textHunk(edits []edit, leftLines, rightLines []string) TextHunk {
+	hunk := TextHunk{LeftStart: edits[0].left + 1, RightStart: edits[0].right + 1, Lines: make([]TextLine, 0, len(edits))}
+	for _, e := range edits {
+		switch e.op {
+		case editMatch:
+			hunk.LeftLines++
+			hunk.RightLines++
+			hunk.Lines = append(hunk.Lines, TextLine{Op: TextContext, Text: leftLines[e.left]})
+		case editDelete:
+			hunk.LeftLines++
+			hunk.Lines = append(hunk.Lines, TextLine{Op: TextDeleted, Text: leftLines[e.left]})
+		case editInsert:
+			hunk.RightLines++
+			hunk.Lines = append(hunk.Lines, TextLine{Op: TextInserted, Text: rightLines[e.right]})
+		}
+	}
+	if hunk.LeftLines == 0 {
+		hunk.LeftStart--
+	}
+	if hunk.RightLines == 0 {
+		hunk.RightStart--
+	}
+	return hunk
+}
+
+// EditScript is a character-level diff of a string change, see WithEditScripts
+type EditScript struct {
+	Runs []EditRun `json:"runs"`
+	// Distance is the number of runes inserted, deleted or substituted, counting
+	// adjacent deletions and insertions as substitutions
+	Distance int `json:"distance"`
+}
+
+// EditRun is a run of runes kept, deleted or inserted by an EditScript
+type EditRun struct {
+	Op   TextOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// String renders the script inline, marking deleted runs as [-...-] and inserted runs
+// as {+...+}, e.g. Jo[-h-]{+a+}n
+func (e *EditScript) String() string {
+	var sb strings.Builder
+	for _, run := range e.Runs {
+		switch run.Op {
+		case TextDeleted:
+			sb.WriteString("[-")
+			sb.WriteString(run.Text)
+			sb.WriteString("-]")
+		case TextInserted:
+			sb.WriteString("{+")
+			sb.WriteString(run.Text)
+			sb.WriteString("+}")
+		default:
+			sb.WriteString(run.Text)
+		}
+	}
+	return sb.String()
+}
+
+// newEditScript computes the rune-level edit script of two strings
+func newEditScript(left, right string) *EditScript {
+	leftRunes, rightRunes := []rune(left), []rune(right)
+	edits := shortestEdits(len(leftRunes), len(rightRunes), func(i, j int) bool {
+		return leftRunes[i] == rightRunes[j]
+	})
+
+	e := &EditScript{}
+	var run []rune
+	op := TextContext
+	flush := func() {
+		if len(run) > 0 {
+			e.Runs = append(e.Runs, EditRun{Op: op, Text: string(run)})
+			run = run[:0]
+		}
+	}
+	deleted, inserted := 0, 0
+	for _, edit := range edits {
+		next, r := TextContext, rune(0)
+		switch edit.op {
+		case editMatch:
+			r = leftRunes[edit.left]
+		case editDelete:
+			next, r = TextDeleted, leftRunes[edit.left]
+			deleted++
+		case editInsert:
+			next, r = TextInserted, rightRunes[edit.right]
+			inserted++
+		}
+		if next != op {
+			flush()
+			op = next
+		}
+		if next == TextContext {
+			e.Distance += max(deleted, inserted)
+			deleted, inserted = 0, 0
+		}
+		run = append(run, r)
+	}
+	flush()
+	e.Distance += max(deleted, inserted)
+	return e
+}
+
+// attachStringDiff attaches a line diff or an edit script, as enabled by WithTextDiff and
+// WithEditScripts, to the last recorded change if it changes a string
+func (c *CompareConfig) attachStringDiff(result *DiffResult) {
+	if !c.TextDiffs && !c.EditScripts || len(result.Diffs) == 0 || result.discard {
+		return
+	}
+	d := result.Diffs[len(result.Diffs)-1].base()
+	leftVal, rightVal := reflect.ValueOf(d.Left), reflect.ValueOf(d.Right)
+	if leftVal.Kind() != reflect.String || rightVal.Kind() != reflect.String {
+		return
+	}
+	left, right := leftVal.String(), rightVal.String()
+	switch {
+	case c.TextDiffs && (strings.Contains(left, "\n") || strings.Contains(right, "\n")):
+		d.Text = newTextDiff(left, right, c.TextDiffContext)
+	case c.EditScripts && (c.EditScriptMaxLength <= 0 ||
+		utf8.RuneCountInString(left) <= c.EditScriptMaxLength && utf8.RuneCountInString(right) <= c.EditScriptMaxLength):
+		d.Edits = newEditScript(left, right)
+	}
+}
@@ -3,6 +3,7 @@ package godiff
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 )
 
@@ -21,16 +22,121 @@ func (h *TimeHandler) Compare(left, right any, path string, result *DiffResult,
 		return fmt.Errorf("TimeHandler received non-time values: left=%T, right=%T", left, right)
 	}
 
-	if !leftTime.Equal(rightTime) {
+	if !timesEqual(leftTime, rightTime, config) {
 		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  leftTime,
-			Right: rightTime,
+			Path:       path,
+			Left:       leftTime,
+			Right:      rightTime,
+			ChangeType: ChangeTypeUpdated,
 		})
 	}
 	return nil
 }
 
+// timesEqual compares two times after applying the configured truncation and tolerance
+func timesEqual(left, right time.Time, config *CompareConfig) bool {
+	if config == nil {
+		return left.Equal(right)
+	}
+	if config.TimeTruncate > 0 {
+		left, right = left.Truncate(config.TimeTruncate), right.Truncate(config.TimeTruncate)
+	}
+	if left.Equal(right) {
+		return true
+	}
+	return config.TimeTolerance > 0 && left.Sub(right).Abs() <= config.TimeTolerance
+}
+
+// EqualMethodHandler handles types with an `Equal(T) bool`, `Cmp(T) int` or `Compare(T) int`
+// method, such as *big.Int or decimal types, by calling the method instead of comparing their
+// internal representation. Methods with a pointer receiver are used as well.
+type EqualMethodHandler struct{}
+
+func (h *EqualMethodHandler) CanHandle(typ reflect.Type) bool {
+	return equalMethodFor(typ) != nil
+}
+
+func (h *EqualMethodHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
+	leftVal := reflect.ValueOf(left)
+	rightVal := reflect.ValueOf(right)
+	if !leftVal.IsValid() || !rightVal.IsValid() || leftVal.Type() != rightVal.Type() {
+		return fmt.Errorf("EqualMethodHandler received mismatched values: left=%T, right=%T", left, right)
+	}
+	method := equalMethodFor(leftVal.Type())
+	if method == nil {
+		return fmt.Errorf("EqualMethodHandler received %T without an Equal, Cmp or Compare method", left)
+	}
+
+	if !method.equal(leftVal, rightVal) {
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
+	}
+	return nil
+}
+
+// equalMethod is an equality method found on a type
+type equalMethod struct {
+	fn      reflect.Value // method function, taking the receiver as first argument
+	pointer bool          // defined on the pointer type
+	boolOut bool          // returns a bool (Equal) rather than an int (Cmp, Compare)
+}
+
+// equalMethodCache caches the equality method per type, nil for types without one
+var equalMethodCache sync.Map // reflect.Type -> *equalMethod
+
+// equalMethodFor returns the equality method of typ, or nil if it has none
+func equalMethodFor(typ reflect.Type) *equalMethod {
+	if cached, ok := equalMethodCache.Load(typ); ok {
+		return cached.(*equalMethod)
+	}
+	method := findEqualMethod(typ)
+	equalMethodCache.Store(typ, method)
+	return method
+}
+
+// findEqualMethod looks up Equal, Cmp and Compare methods taking the receiver's own type
+func findEqualMethod(typ reflect.Type) *equalMethod {
+	for _, name := range []string{"Equal", "Cmp", "Compare"} {
+		for _, pointer := range []bool{false, true} {
+			recv := typ
+			if pointer {
+				if typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Interface {
+					continue
+				}
+				recv = reflect.PointerTo(typ)
+			}
+			m, ok := recv.MethodByName(name)
+			if !ok {
+				continue
+			}
+			ft := m.Type
+			if ft.NumIn() != 2 || ft.In(1) != recv || ft.NumOut() != 1 {
+				continue
+			}
+			if name == "Equal" && ft.Out(0).Kind() == reflect.Bool {
+				return &equalMethod{fn: m.Func, pointer: pointer, boolOut: true}
+			}
+			if name != "Equal" && ft.Out(0).Kind() == reflect.Int {
+				return &equalMethod{fn: m.Func, pointer: pointer}
+			}
+		}
+	}
+	return nil
+}
+
+// equal calls the method on two values of its type. Nil pointers are only equal to each other.
+func (m *equalMethod) equal(left, right reflect.Value) bool {
+	if m.pointer {
+		left, right = addressableCopy(left).Addr(), addressableCopy(right).Addr()
+	} else if left.Kind() == reflect.Pointer && (left.IsNil() || right.IsNil()) {
+		return left.IsNil() && right.IsNil()
+	}
+	out := m.fn.Call([]reflect.Value{left, right})[0]
+	if m.boolOut {
+		return out.Bool()
+	}
+	return out.Int() == 0
+}
+
 // InterfaceHandler handles any types by comparing their underlying values
 type InterfaceHandler struct{}
 
@@ -51,23 +157,25 @@ func (h *InterfaceHandler) Compare(left, right any, path string, result *DiffRes
 
 	if leftIsNil {
 		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  nil,
-			Right: right,
+			Path:       path,
+			Left:       nil,
+			Right:      right,
+			ChangeType: ChangeTypeAdded,
 		})
 		return nil
 	}
 
 	if rightIsNil {
 		result.Diffs = append(result.Diffs, &Diff{
-			Path:  path,
-			Left:  left,
-			Right: nil,
+			Path:       path,
+			Left:       left,
+			Right:      nil,
+			ChangeType: ChangeTypeRemoved,
 		})
 		return nil
 	}
 
-	return compareValues(path, leftVal.Elem().Interface(), rightVal.Elem().Interface(), result, config)
+	return compareValues(config.typedPath(path).with(PathStep{Kind: StepInterface}), leftVal.Elem().Interface(), rightVal.Elem().Interface(), result, config)
 }
 
 // FunctionHandler handles function types (compares by reference or ignores based on config)
@@ -87,7 +195,7 @@ func (h *FunctionHandler) Compare(left, right any, path string, result *DiffResu
 	}
 
 	if !leftVal.IsValid() || !rightVal.IsValid() {
-		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
 		return nil
 	}
 
@@ -96,12 +204,12 @@ func (h *FunctionHandler) Compare(left, right any, path string, result *DiffResu
 	}
 
 	if leftVal.IsNil() || rightVal.IsNil() {
-		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
 		return nil
 	}
 
 	if leftVal.Pointer() != rightVal.Pointer() {
-		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
 	}
 	return nil
 }
@@ -115,7 +223,7 @@ func (h *ChannelHandler) CanHandle(typ reflect.Type) bool {
 
 func (h *ChannelHandler) Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error {
 	if left != right {
-		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right})
+		result.Diffs = append(result.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right)})
 	}
 	return nil
 }
@@ -124,6 +232,15 @@ func (h *ChannelHandler) Compare(left, right any, path string, result *DiffResul
 func DefaultTypeHandlers() []TypeHandler {
 	return []TypeHandler{
 		&TimeHandler{},
+		&BigNumberHandler{},
+		&NetipHandler{},
+		&IPHandler{},
+		&URLHandler{},
+		&RegexpHandler{},
+		&LocationHandler{},
+		&RawJSONHandler{},
+		&SQLNullHandler{},
+		&BytesHandler{},
 		&InterfaceHandler{},
 		&FunctionHandler{},
 		&ChannelHandler{},
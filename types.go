@@ -1,7 +1,12 @@
 package godiff
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"reflect"
+	"strings"
+	"time"
 )
 
 // ChangeType represents the type of change detected
@@ -11,97 +16,448 @@ const (
 	ChangeTypeAdded   ChangeType = "ADDED"
 	ChangeTypeRemoved ChangeType = "REMOVED"
 	ChangeTypeUpdated ChangeType = "UPDATED"
+	// ChangeTypeTypeChanged marks values whose dynamic types differ
+	ChangeTypeTypeChanged ChangeType = "TYPE_CHANGED"
+	// ChangeTypeMoved marks slice elements that changed position (see SliceDiff.FromIndex)
+	ChangeTypeMoved ChangeType = "MOVED"
 )
 
+// ContainerKind identifies the kind of value a change was found in
+type ContainerKind string
+
+const (
+	ContainerValue  ContainerKind = "value"
+	ContainerMap    ContainerKind = "map"
+	ContainerSlice  ContainerKind = "slice"
+	ContainerStruct ContainerKind = "struct"
+)
+
+// Change is a single difference recorded in a DiffResult. It is implemented by
+// *Diff, *MapDiff, *SliceDiff and *StructDiff only; use a type switch on those
+// types to access the container specific fields. The accessors are named Location,
+// LeftValue and RightValue because Path, Left and Right are already fields of Diff,
+// which every implementation embeds, and a type cannot have a field and a method of
+// the same name.
+type Change interface {
+	// Location returns the typed location of the differing value (see Diff.Steps)
+	Location() Path
+	// LeftValue returns the left value, nil if the value was added
+	LeftValue() any
+	// RightValue returns the right value, nil if the value was removed
+	RightValue() any
+	// Kind returns the type of change: ADDED, REMOVED, UPDATED, TYPE_CHANGED or MOVED
+	Kind() ChangeType
+	// Container returns the kind of value the change was found in
+	Container() ContainerKind
+	// base returns the embedded Diff and seals the interface
+	base() *Diff
+}
+
 // Diff represents a single difference between two values
 type Diff struct {
-	Path  string // JSON path to the differing field
-	Left  any    // Left value (nil if added)
-	Right any    // Right value (nil if removed)
+	Path       string     // JSON path to the differing field
+	Left       any        // Left value (nil if added)
+	Right      any        // Right value (nil if removed)
+	ChangeType ChangeType // Type of change: ADDED, REMOVED, UPDATED, TYPE_CHANGED, MOVED
+	// Steps is the typed location of the differing value. Unlike Path, it includes
+	// the element index of a SliceDiff.
+	Steps Path
+	// Text is the line diff of a multi-line string change, see WithTextDiff
+	Text *TextDiff
+	// Edits is the character-level edit script of a string change, see WithEditScripts
+	Edits *EditScript
 }
 
 // MapDiff represents a difference in a map
 type MapDiff struct {
 	Diff
-	Key        any        // The map key that changed
-	ChangeType ChangeType // Type of change: ADDED, REMOVED, UPDATED
+	Key any // The map key that changed
 }
 
 // SliceDiff represents a difference in a slice
 type SliceDiff struct {
 	Diff
-	Index      int        // The slice index that changed
-	ChangeType ChangeType // Type of change: ADDED, REMOVED, UPDATED
+	Index     int // The slice index that changed: the right index, or the left index if removed
+	FromIndex int // Index of the element in the left slice, -1 if added
+	ToIndex   int // Index of the element in the right slice, -1 if removed
 }
 
 // StructDiff represents a difference in a struct
 type StructDiff struct {
 	Diff
-	FieldName  string     // The struct field name that changed
-	ChangeType ChangeType // Type of change: ADDED, REMOVED, UPDATED
+	FieldName string // The struct field name that changed
+}
+
+// Location returns the typed location of the differing value
+func (d *Diff) Location() Path { return valueSteps(d) }
+
+// LeftValue returns the left value
+func (d *Diff) LeftValue() any { return d.Left }
+
+// RightValue returns the right value
+func (d *Diff) RightValue() any { return d.Right }
+
+// Kind returns the type of change, UPDATED if none was recorded
+func (d *Diff) Kind() ChangeType {
+	if d.ChangeType == "" {
+		return ChangeTypeUpdated
+	}
+	return d.ChangeType
 }
 
+// Container returns ContainerValue
+func (d *Diff) Container() ContainerKind { return ContainerValue }
+
+func (d *Diff) base() *Diff { return d }
+
+// Container returns ContainerMap
+func (d *MapDiff) Container() ContainerKind { return ContainerMap }
+
+// Container returns ContainerSlice
+func (d *SliceDiff) Container() ContainerKind { return ContainerSlice }
+
+// Container returns ContainerStruct
+func (d *StructDiff) Container() ContainerKind { return ContainerStruct }
+
 // DiffResult contains all differences found between two values
 type DiffResult struct {
-	Diffs []any // Can hold Diff, MapDiff, SliceDiff, or StructDiff
+	Diffs []Change // Holds *Diff, *MapDiff, *SliceDiff or *StructDiff values
+	// Truncated is set if the comparison stopped after WithMaxDiffs changes
+	Truncated bool
+	// EstimatedTotal is the number of changes, extrapolated from the portion of the values
+	// compared if the result is truncated. It is 0 if the portion is not known, such as when
+	// the comparison stopped in a struct field outside any slice or below a map entry.
+	EstimatedTotal int
+	// left and right are the compared root values, used to resolve serialized names (internal use only)
+	left, right any
+	// limit stops the comparison once this many changes are recorded, 0 for no limit;
+	// only discards changes outside the OnlyFields before the limit is checked (internal use only)
+	limit int
+	only  *allowMatcher
+	// checked is the number of changes classified by counted, allowed the number of them
+	// inside the OnlyFields (internal use only)
+	checked, allowed int
+	// discard records every change as the shared differs placeholder, for comparisons that
+	// only ask whether the values are equal (internal use only)
+	discard bool
+	// truncatedAt is the location the comparison stopped at, and progress the share of the
+	// container there that was compared if it is not addressed by an index (internal use only)
+	truncatedAt Path
+	progress    float64
+}
+
+// limitReached reports whether the result holds as many changes as its limit
+func (dr *DiffResult) limitReached() bool {
+	return dr.limit > 0 && dr.counted() >= dr.limit
+}
+
+// counted returns the number of recorded changes that count towards the limit: all of
+// them, or only those inside the OnlyFields. Changes outside them are kept until the
+// comparison is done, as type handlers refer to the changes they record by position.
+func (dr *DiffResult) counted() int {
+	if dr.only == nil {
+		return len(dr.Diffs)
+	}
+	for ; dr.checked < len(dr.Diffs); dr.checked++ {
+		if !dr.only.excludes(dr.Diffs[dr.checked]) {
+			dr.allowed++
+		}
+	}
+	return dr.allowed
+}
+
+// remaining returns how many more changes the result takes before its limit is reached.
+// limited is false if there is no limit, or if changes outside the OnlyFields are still
+// to be discarded and recorded changes therefore do not count towards it.
+func (dr *DiffResult) remaining() (n int, limited bool) {
+	if dr.limit <= 0 || dr.only != nil {
+		return 0, false
+	}
+	return max(dr.limit-len(dr.Diffs), 0), true
+}
+
+// truncate marks the result as truncated at the value addressed by path and returns
+// errDiffLimit to abort the comparison
+func (dr *DiffResult) truncate(path Path) error {
+	dr.Truncated = true
+	dr.truncatedAt = path
+	return errDiffLimit
+}
+
+// truncateWithin is truncate for a comparison that stopped after done of total steps
+// through the map, keyed or unordered slice at path, whose entries are not visited in the order
+// of an index
+func (dr *DiffResult) truncateWithin(path Path, done, total int) error {
+	dr.progress = float64(done) / float64(total)
+	return dr.truncate(path)
+}
+
+// estimateTotal extrapolates the number of changes of a truncated comparison from the
+// fraction of the left value that lies before the location the comparison stopped at.
+// Only slice and array positions and the progress recorded by truncateWithin contribute:
+// struct fields differ too much in size to be counted as equal shares, and the entries of
+// a map compared before the one the comparison stopped in are not known. It returns 0 if
+// no fraction is known.
+func (dr *DiffResult) estimateTotal() int {
+	fraction, scale := 0.0, 1.0
+	v := reflect.ValueOf(dr.left)
+	complete := true
+	for _, step := range dr.truncatedAt {
+		v = indirectValue(v)
+		if v.IsValid() && isJSONDocument(v) {
+			// the steps below a JSON document address its decoded members
+			var doc any
+			if json.Unmarshal(jsonDocument(v), &doc) != nil {
+				complete = false
+				break
+			}
+			v = indirectValue(reflect.ValueOf(&doc).Elem())
+		}
+		if v.Kind() == reflect.Map {
+			complete = false
+			break
+		}
+		if (step.Kind == StepIndex || step.Kind == StepIdentity) && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) &&
+			step.Index >= 0 && step.Index < v.Len() {
+			count := float64(v.Len())
+			fraction += scale * float64(step.Index) / count
+			scale /= count
+		}
+		if v = lookupPath(v, Path{step}); !v.IsValid() {
+			complete = false
+			break
+		}
+	}
+	if complete {
+		fraction += scale * dr.progress
+	}
+	if fraction <= 0 {
+		return 0
+	}
+	return max(len(dr.Diffs), int(math.Round(float64(len(dr.Diffs))/fraction)))
 }
 
 // AddDiff adds a basic Diff to the result
 func (dr *DiffResult) AddDiff(path string, left, right any) {
-	dr.Diffs = append(dr.Diffs, &Diff{Path: path, Left: left, Right: right})
+	dr.Diffs = append(dr.Diffs, &Diff{Path: path, Left: left, Right: right, ChangeType: changeTypeFor(left, right), Steps: parsedSteps(path)})
 }
 
 // AddStructDiff adds a StructDiff to the result
 func (dr *DiffResult) AddStructDiff(path, fieldName string, left, right any, changeType ChangeType) {
 	dr.Diffs = append(dr.Diffs, &StructDiff{
-		Diff:       Diff{Path: path, Left: left, Right: right},
-		FieldName:  fieldName,
-		ChangeType: changeType,
+		Diff:      Diff{Path: path, Left: left, Right: right, ChangeType: changeType, Steps: parsedSteps(path)},
+		FieldName: fieldName,
 	})
 }
 
 // AddSliceDiff adds a SliceDiff to the result
 func (dr *DiffResult) AddSliceDiff(path string, index int, left, right any, changeType ChangeType) {
+	from, to := sliceIndexes(index, changeType)
 	dr.Diffs = append(dr.Diffs, &SliceDiff{
-		Diff:       Diff{Path: path, Left: left, Right: right},
-		Index:      index,
-		ChangeType: changeType,
+		Diff:      Diff{Path: path, Left: left, Right: right, ChangeType: changeType, Steps: parsedSteps(path).index(index)},
+		Index:     index,
+		FromIndex: from,
+		ToIndex:   to,
 	})
 }
 
 // AddMapDiff adds a MapDiff to the result
 func (dr *DiffResult) AddMapDiff(path string, key, left, right any, changeType ChangeType) {
 	dr.Diffs = append(dr.Diffs, &MapDiff{
-		Diff:       Diff{Path: path, Left: left, Right: right},
-		Key:        key,
-		ChangeType: changeType,
+		Diff: Diff{Path: path, Left: left, Right: right, ChangeType: changeType, Steps: parsedSteps(path)},
+		Key:  key,
+	})
+}
+
+// differs stands in for the changes of a DiffResult that discards them. Its Steps are
+// set so that compareWithHandler leaves it alone.
+var differs = &Diff{Steps: Path{}}
+
+// addDiff records a basic Diff at path
+func (dr *DiffResult) addDiff(path Path, left, right any, changeType ChangeType) {
+	if dr.discard {
+		dr.Diffs = append(dr.Diffs, differs)
+		return
+	}
+	dr.Diffs = append(dr.Diffs, &Diff{Path: path.String(), Left: left, Right: right, ChangeType: changeType, Steps: path})
+}
+
+// addStructDiff records a StructDiff for the field addressed by the last step of path
+func (dr *DiffResult) addStructDiff(path Path, left, right any, changeType ChangeType) {
+	if dr.discard {
+		dr.Diffs = append(dr.Diffs, differs)
+		return
+	}
+	_, last := path.Parent()
+	dr.Diffs = append(dr.Diffs, &StructDiff{
+		Diff:      Diff{Path: path.String(), Left: left, Right: right, ChangeType: changeType, Steps: path},
+		FieldName: last.fieldName(),
+	})
+}
+
+// addSliceDiff records a SliceDiff for the element at index of the slice at path
+func (dr *DiffResult) addSliceDiff(path Path, index int, left, right any, changeType ChangeType) {
+	from, to := sliceIndexes(index, changeType)
+	dr.addSliceEdit(path, from, to, left, right, changeType)
+}
+
+// addSliceEdit records a SliceDiff for an element found at index from of the left slice
+// and index to of the right slice, either of which is -1 if the element is missing there
+func (dr *DiffResult) addSliceEdit(path Path, from, to int, left, right any, changeType ChangeType) {
+	if dr.discard {
+		dr.Diffs = append(dr.Diffs, differs)
+		return
+	}
+	index := to
+	if index < 0 {
+		index = from
+	}
+	dr.Diffs = append(dr.Diffs, &SliceDiff{
+		Diff:      Diff{Path: path.String(), Left: left, Right: right, ChangeType: changeType, Steps: path.index(index)},
+		Index:     index,
+		FromIndex: from,
+		ToIndex:   to,
+	})
+}
+
+// addKeyedSliceDiff records a SliceDiff for an element of a slice matched by identity,
+// addressed by an identity step when the element has a key
+func (dr *DiffResult) addKeyedSliceDiff(path Path, name string, key any, from, to int, left, right any, changeType ChangeType) {
+	dr.addSliceEdit(path, from, to, left, right, changeType)
+	if key != nil && !dr.discard {
+		d := dr.Diffs[len(dr.Diffs)-1].(*SliceDiff)
+		d.Steps = path.identity(name, key, d.Index)
+	}
+}
+
+// sliceIndexes returns the left and right index of an element at the same index on both sides
+func sliceIndexes(index int, changeType ChangeType) (from, to int) {
+	switch changeType {
+	case ChangeTypeAdded:
+		return -1, index
+	case ChangeTypeRemoved:
+		return index, -1
+	default:
+		return index, index
+	}
+}
+
+// addMapDiff records a MapDiff at path, which ends with the map key step
+func (dr *DiffResult) addMapDiff(path Path, key, left, right any, changeType ChangeType) {
+	if dr.discard {
+		dr.Diffs = append(dr.Diffs, differs)
+		return
+	}
+	dr.Diffs = append(dr.Diffs, &MapDiff{
+		Diff: Diff{Path: path.String(), Left: left, Right: right, ChangeType: changeType, Steps: path},
+		Key:  key,
 	})
 }
 
+// SliceAlgorithm selects how ordered slices are compared
+type SliceAlgorithm int
+
+const (
+	// SlicePositional compares the elements at equal indexes, reporting trailing
+	// elements as added or removed. It is the fastest algorithm.
+	SlicePositional SliceAlgorithm = iota
+	// SliceLCS aligns both slices along their longest common subsequence, so
+	// insertions and deletions are reported as such instead of shifting every
+	// following element. Paired deletions and insertions are reported as updates.
+	SliceLCS
+)
+
 // CompareConfig holds configuration options for the comparison.
 // Note: CompareConfig is not thread-safe. Do not share a single config instance
 // across multiple concurrent Compare calls.
 type CompareConfig struct {
 	// IgnoreFields is a list of field paths to ignore during comparison (e.g., "User.Password").
+	// Entries may be wildcard patterns such as "Items[*].UpdatedAt" or "**.Secret", or regular
+	// expressions prefixed with "re:".
 	IgnoreFields []string
+	// PathNaming, if set, names struct fields in paths. Defaults to the Go field names.
+	PathNaming PathNaming
+	// SkipOmittedFields, if true, skips struct fields the PathNaming omits.
+	SkipOmittedFields bool
+	// UnexportedFields, if true, compares the unexported fields of all structs.
+	UnexportedFields bool
+	// UnexportedTypes lists struct types whose unexported fields are compared.
+	UnexportedTypes map[reflect.Type]bool
+	// OnlyFields, if not empty, restricts the comparison to these paths and everything below
+	// them. Entries use the wildcard syntax of IgnoreFields, e.g. "Orders[*].Total".
+	OnlyFields []string
 	// IgnoreSliceOrder, if true, ignores element order when comparing slices.
 	IgnoreSliceOrder bool
+	// SliceAlgorithm selects how ordered slices are aligned. Defaults to SlicePositional.
+	SliceAlgorithm SliceAlgorithm
+	// SliceKeys maps slice paths to the identity key their elements are paired by:
+	// a struct field name or a func(elem any) any (see WithSliceKey).
+	SliceKeys map[string]any
+	// DetectMoves, if true, reports ordered slice elements that changed position as MOVED.
+	DetectMoves bool
+	// NilEqualsEmpty, if true, treats nil and absent values as equal to empty slices and maps.
+	NilEqualsEmpty bool
+	// ZeroEqualsMissing, if true, treats nil and absent values as equal to zero values.
+	ZeroEqualsMissing bool
 	// CompareNumericValues, if true, compares numeric values across different types.
 	// For example, int(1) and int64(1) would be considered equal.
 	// This applies to all integer and floating-point types.
 	CompareNumericValues bool
+	// FloatAbsTolerance and FloatRelTolerance, if positive, treat float and complex values
+	// as equal when they differ by at most the absolute tolerance or by at most the relative
+	// tolerance times the larger magnitude.
+	FloatAbsTolerance float64
+	FloatRelTolerance float64
+	// TimeTolerance, if positive, treats time.Time values at most this far apart as equal.
+	TimeTolerance time.Duration
+	// TimeTruncate, if positive, truncates time.Time values to a multiple of it before comparing.
+	TimeTruncate time.Duration
+	// TextDiffs, if true, attaches a line diff with TextDiffContext lines of context to
+	// changes of multi-line strings.
+	TextDiffs       bool
+	TextDiffContext int
+	// EditScripts, if true, attaches a character-level edit script to changes of strings
+	// of at most EditScriptMaxLength runes (0 means any length).
+	EditScripts         bool
+	EditScriptMaxLength int
 	// CustomComparators is a map of custom comparison functions for specific types.
 	CustomComparators map[reflect.Type]func(left, right any, config *CompareConfig) (bool, error)
+	// NamedComparators are comparison functions that struct fields select by name with
+	// the `diff:"compare=name"` tag.
+	NamedComparators map[string]func(left, right any, config *CompareConfig) (bool, error)
 	// TypeHandlers is a list of handlers for comparing custom or complex types.
 	TypeHandlers []TypeHandler
+	// EqualMethods, if true, compares values of types with an Equal, Cmp or Compare method
+	// by calling it, after the TypeHandlers.
+	EqualMethods bool
+	// Filters are called before descending into a struct field, map entry or slice element;
+	// the value is skipped if any of them returns false.
+	Filters []func(ctx FilterContext) bool
 	// MaxDepth limits the recursion depth for comparison. 0 means unlimited.
 	MaxDepth int
+	// MaxDiffs, if positive, stops the comparison once this many changes are recorded.
+	MaxDiffs int
 	// visitedPairs tracks visited pointer pairs for cycle detection (internal use only)
 	visitedPairs map[[2]uintptr]bool
-	// ignoreFieldsSet is a pre-computed set for O(1) lookup (internal use only)
-	ignoreFieldsSet map[string]bool
+	// ignoreCase and ignoreWhitespace relax string comparison for fields tagged
+	// `diff:"ignoreCase"` or `diff:"ignoreWhitespace"` (internal use only)
+	ignoreCase       bool
+	ignoreWhitespace bool
+	// ignoreMatcher is the compiled form of IgnoreFields (internal use only)
+	ignoreMatcher *pathMatcher
+	// sliceKeys is the compiled form of SliceKeys (internal use only)
+	sliceKeys map[string]*sliceKey
+	// onlyMatcher is the compiled form of OnlyFields (internal use only)
+	onlyMatcher *allowMatcher
+	// insideOnlyFields is set while comparing below an allowed path (internal use only)
+	insideOnlyFields bool
 	// currentDepth tracks the current recursion depth (internal use only)
 	currentDepth int
+	// currentPath is the typed path of the value passed to a TypeHandler (internal use only)
+	currentPath Path
+	// plans holds what a Differ resolved for the types reachable from its type parameter
+	// (internal use only)
+	plans map[reflect.Type]*typePlan
 }
 
 // TypeHandler defines an interface for handling specific types during comparison
@@ -110,12 +466,140 @@ type TypeHandler interface {
 	Compare(left, right any, path string, result *DiffResult, config *CompareConfig) error
 }
 
+// PathNaming returns the name a struct field is rendered with in paths. An empty name
+// falls back to the Go field name; omit reports fields the naming scheme excludes.
+type PathNaming func(field reflect.StructField) (name string, omit bool)
+
+// Built-in path naming schemes
+var (
+	// NamingGo uses the Go field names
+	NamingGo PathNaming = func(field reflect.StructField) (string, bool) { return field.Name, false }
+	// NamingJSON uses the json tag names and omits fields tagged `json:"-"`
+	NamingJSON PathNaming = jsonFieldName
+	// NamingYAML uses the yaml tag names, defaulting to the lowercased field name, and
+	// omits fields tagged `yaml:"-"`
+	NamingYAML PathNaming = yamlFieldName
+)
+
+// yamlFieldName returns the key gopkg.in/yaml uses for field
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name != "" {
+		return name, false
+	}
+	if strings.Contains(opts, "inline") {
+		return "", false
+	}
+	return strings.ToLower(field.Name), false
+}
+
+// FilterContext describes a struct field, map entry or slice element about to be compared
+type FilterContext struct {
+	Path       Path                 // path of the value
+	ParentType reflect.Type         // type of the enclosing struct, map or slice
+	Field      *reflect.StructField // struct field, nil unless the value is a field
+	MapKey     any                  // map key, nil unless the value is a map entry
+	Index      int                  // slice index, -1 unless the value is a slice element
+	Left       any                  // left value, nil if only present on the right
+	Right      any                  // right value, nil if only present on the left
+}
+
+// sliceKey extracts the identity of slice elements
+type sliceKey struct {
+	name  string             // name used in identity path steps
+	field string             // struct field holding the key, matched case-insensitively
+	fn    func(elem any) any // key function, used instead of field
+}
+
+// newSliceKey creates a sliceKey from a field name or key function
+func newSliceKey(key any) (*sliceKey, error) {
+	switch k := key.(type) {
+	case string:
+		if k == "" {
+			return nil, fmt.Errorf("empty key field name")
+		}
+		return &sliceKey{name: k, field: k}, nil
+	case func(elem any) any:
+		if k == nil {
+			return nil, fmt.Errorf("nil key function")
+		}
+		return &sliceKey{name: "key", fn: k}, nil
+	default:
+		return nil, fmt.Errorf("expected a field name or func(elem any) any, got %T", key)
+	}
+}
+
+// of returns the identity of elem. ok is false for elements without a comparable key,
+// such as nil pointers or structs lacking the key field.
+func (k *sliceKey) of(elem reflect.Value) (key any, ok bool) {
+	if k.fn != nil {
+		if !elem.CanInterface() {
+			return nil, false
+		}
+		key = k.fn(elem.Interface())
+	} else {
+		for elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Interface {
+			if elem.IsNil() {
+				return nil, false
+			}
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, found := elem.Type().FieldByName(k.field)
+		if !found {
+			field, found = elem.Type().FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, k.field) })
+		}
+		if !found || !field.IsExported() {
+			return nil, false
+		}
+		key = elem.FieldByIndex(field.Index).Interface()
+	}
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return nil, false
+	}
+	return key, true
+}
+
+// sliceKeyFor returns the identity key configured for the slice at path, matching
+// the full dotted path first and its field names alone second
+func (c *CompareConfig) sliceKeyFor(path Path) *sliceKey {
+	if len(c.sliceKeys) == 0 {
+		return nil
+	}
+	if key, ok := c.sliceKeys[path.String()]; ok {
+		return key
+	}
+	var names []string
+	for _, step := range path {
+		if step.Kind == StepField {
+			names = append(names, step.fieldName())
+		}
+	}
+	return c.sliceKeys[strings.Join(names, ".")]
+}
+
+// typedPath returns the typed form of a path handed to a TypeHandler, parsing it
+// if the handler was invoked outside of Compare
+func (c *CompareConfig) typedPath(path string) Path {
+	if c.currentPath.String() == path {
+		return c.currentPath
+	}
+	return parsedSteps(path)
+}
+
 // DefaultCompareConfig returns the default configuration
 func DefaultCompareConfig() *CompareConfig {
 	return &CompareConfig{
 		IgnoreFields:     []string{},
 		IgnoreSliceOrder: false,
 		TypeHandlers:     DefaultTypeHandlers(),
+		TextDiffContext:  3,
 		visitedPairs:     make(map[[2]uintptr]bool),
 	}
 }
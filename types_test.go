@@ -1023,59 +1023,41 @@ func TestStructWithSliceTags(t *testing.T) {
 
 }
 
-func TestHasExactDiffTagEdgeCases(t *testing.T) {
+func TestParseDiffTag(t *testing.T) {
 	tests := []struct {
 		name     string
 		diffTag  string
-		tagValue string
-		expected bool
+		expected fieldTag
 	}{
-		{
-			name:     "empty tag",
-			diffTag:  "",
-			tagValue: "ignore",
-			expected: false,
-		},
-		{
-			name:     "single matching tag",
-			diffTag:  "ignore",
-			tagValue: "ignore",
-			expected: true,
-		},
-		{
-			name:     "multiple tags with match",
-			diffTag:  "ignore,ignoreOrder",
-			tagValue: "ignoreOrder",
-			expected: true,
-		},
-		{
-			name:     "multiple tags without match",
-			diffTag:  "ignore,ignoreOrder",
-			tagValue: "notfound",
-			expected: false,
-		},
-		{
-			name:     "tag with spaces",
-			diffTag:  "ignore , ignoreOrder",
-			tagValue: "ignore",
-			expected: true,
-		},
-		{
-			name:     "partial match should not work",
-			diffTag:  "ignoreOrder",
-			tagValue: "ignore",
-			expected: false,
-		},
+		{"empty tag", "", fieldTag{}},
+		{"single flag", "ignore", fieldTag{ignore: true}},
+		{"multiple flags", "ignore,ignoreOrder", fieldTag{ignore: true, ignoreOrder: true}},
+		{"flags with spaces", "ignore , ignoreOrder", fieldTag{ignore: true, ignoreOrder: true}},
+		{"partial name is not a flag", "ignoreOrder", fieldTag{ignoreOrder: true}},
+		{"values", "name=id, key=ID, compare=money", fieldTag{name: "id", key: "ID", comparator: "money"}},
+		{"float tolerance", "tolerance=0.01", fieldTag{floatTolerance: 0.01}},
+		{"time tolerance", "tolerance=1s,truncate=1ms", fieldTag{timeTolerance: time.Second, timeTruncate: time.Millisecond}},
+		{"string flags", "ignoreCase,ignoreWhitespace,redact", fieldTag{ignoreCase: true, ignoreWhitespace: true, redact: true}},
+		{"depth flags", "shallow,omitEmptyEqual", fieldTag{shallow: true, omitEmptyEqual: true}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := hasDiffTag(tt.diffTag, tt.tagValue)
-			if result != tt.expected {
-				t.Errorf("hasDiffTag(%q, %q) = %v, expected %v", tt.diffTag, tt.tagValue, result, tt.expected)
+			tag, err := parseDiffTag(tt.diffTag)
+			if err != nil {
+				t.Fatalf("parseDiffTag(%q) failed: %v", tt.diffTag, err)
+			}
+			if tag != tt.expected {
+				t.Errorf("parseDiffTag(%q) = %+v, expected %+v", tt.diffTag, tag, tt.expected)
 			}
 		})
 	}
+
+	for _, invalid := range []string{"invalid", "ignore=true", "name", "key=", "tolerance=abc", "truncate=1", "deep,shallow"} {
+		if _, err := parseDiffTag(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
 }
 
 func TestInterfaceHandlerDirectUsage(t *testing.T) {
@@ -1203,7 +1185,7 @@ func TestComparePointersAdditionalEdgeCases(t *testing.T) {
 			rightVal := reflect.ValueOf(tt.right)
 
 			config := DefaultCompareConfig()
-			err := comparePointers("test", leftVal, rightVal, result, config)
+			err := comparePointers(Path{}.field("test"), leftVal, rightVal, result, config)
 			if err != nil {
 				t.Fatalf("comparePointers failed: %v", err)
 			}
@@ -1412,6 +1394,24 @@ func TestCompareNumericValues(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("different values are updated", func(t *testing.T) {
+		result, err := Compare(int(1), float64(2), WithCompareNumericValues())
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 1 || result.Diffs[0].Kind() != ChangeTypeUpdated {
+			t.Errorf("Expected 1 UPDATED difference, got %s", result.String())
+		}
+
+		result, err = Compare(map[string]any{"n": int(1)}, map[string]any{"n": float64(2)}, WithCompareNumericValues())
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		if len(result.Diffs) != 1 || result.Diffs[0].Kind() != ChangeTypeUpdated {
+			t.Errorf("Expected 1 UPDATED map difference, got %s", result.String())
+		}
+	})
 }
 
 func TestCompareNumericValuesDisabled(t *testing.T) {
@@ -1509,3 +1509,131 @@ func TestCompareNumericValuesInStructs(t *testing.T) {
 		}
 	})
 }
+
+func TestChangeInterface(t *testing.T) {
+	type item struct {
+		Name string
+		Tags []string
+		Meta map[string]int
+	}
+
+	result, err := Compare(
+		item{Name: "a", Tags: []string{"x"}, Meta: map[string]int{"old": 1}},
+		item{Name: "b", Tags: []string{"x", "y"}, Meta: map[string]int{}},
+	)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	expected := map[string]struct {
+		kind      ChangeType
+		container ContainerKind
+		left      any
+		right     any
+	}{
+		"Name":      {ChangeTypeUpdated, ContainerStruct, "a", "b"},
+		"Tags[1]":   {ChangeTypeAdded, ContainerSlice, nil, "y"},
+		"Meta[old]": {ChangeTypeRemoved, ContainerMap, 1, nil},
+	}
+	if len(result.Diffs) != len(expected) {
+		t.Fatalf("Expected %d diffs, got %d: %s", len(expected), len(result.Diffs), result.String())
+	}
+	for _, change := range result.Diffs {
+		location := change.Location().String()
+		want, ok := expected[location]
+		if !ok {
+			t.Errorf("Unexpected change at %q", location)
+			continue
+		}
+		if change.Kind() != want.kind || change.Container() != want.container {
+			t.Errorf("Expected %s %s at %q, got %s %s", want.kind, want.container, location, change.Kind(), change.Container())
+		}
+		if change.LeftValue() != want.left || change.RightValue() != want.right {
+			t.Errorf("Expected %v -> %v at %q, got %v -> %v", want.left, want.right, location, change.LeftValue(), change.RightValue())
+		}
+	}
+
+	if kind := (&Diff{Path: "x"}).Kind(); kind != ChangeTypeUpdated {
+		t.Errorf("Expected plain Diff without change type to report UPDATED, got %s", kind)
+	}
+}
+
+func TestChangeTypeClassification(t *testing.T) {
+	type node struct {
+		Value    any
+		Next     *node
+		Callback func()
+	}
+	callback := func() {}
+
+	tests := []struct {
+		name     string
+		left     any
+		right    any
+		expected map[string]ChangeType
+	}{
+		{
+			name:     "nil root to value",
+			left:     nil,
+			right:    1,
+			expected: map[string]ChangeType{"": ChangeTypeAdded},
+		},
+		{
+			name:     "value root to nil",
+			left:     "x",
+			right:    nil,
+			expected: map[string]ChangeType{"": ChangeTypeRemoved},
+		},
+		{
+			name:     "type mismatch",
+			left:     1,
+			right:    "1",
+			expected: map[string]ChangeType{"": ChangeTypeTypeChanged},
+		},
+		{
+			name:  "nested nil transitions",
+			left:  node{Value: 1, Next: &node{Value: "a"}},
+			right: node{Value: nil, Callback: callback},
+			expected: map[string]ChangeType{
+				"Value":    ChangeTypeRemoved,
+				"Next":     ChangeTypeRemoved,
+				"Callback": ChangeTypeAdded,
+			},
+		},
+		{
+			name:     "interface type change",
+			left:     node{Value: 1},
+			right:    node{Value: 1.0},
+			expected: map[string]ChangeType{"Value": ChangeTypeTypeChanged},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compare(tt.left, tt.right)
+			if err != nil {
+				t.Fatalf("Compare failed: %v", err)
+			}
+			if len(result.Diffs) != len(tt.expected) {
+				t.Fatalf("Expected %d diffs, got %d: %s", len(tt.expected), len(result.Diffs), result.String())
+			}
+			for _, change := range result.Diffs {
+				d := change.base()
+				if want := tt.expected[d.Path]; d.ChangeType != want {
+					t.Errorf("Expected %s for %q, got %s", want, d.Path, d.ChangeType)
+				}
+			}
+		})
+	}
+
+	result, err := Compare([]string{"a", "b"}, []string{"b", "c"}, WithIgnoreSliceOrder())
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	for _, change := range result.Diffs {
+		d := change.(*Diff)
+		if (d.Left == "a" && d.ChangeType != ChangeTypeRemoved) || (d.Right == "c" && d.ChangeType != ChangeTypeAdded) {
+			t.Errorf("Unexpected change type %s for unordered %v -> %v", d.ChangeType, d.Left, d.Right)
+		}
+	}
+}